@@ -0,0 +1,28 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// commands maps a name given on the command line, e.g. "server sanitize",
+// to a one-off task. The database is initialized before it runs.
+var commands = map[string]func(args []string) error{
+	"sanitize":        Sanitize,
+	"migrate-storage": MigrateStorage,
+}
+
+// Run executes the named command
+func Run(name string, args []string) error {
+	cmd, ok := commands[name]
+	if !ok {
+		names := make([]string, 0, len(commands))
+		for n := range commands {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown command %q (available: %s)", name, strings.Join(names, ", "))
+	}
+	return cmd(args)
+}
@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"log"
+
+	"kunals-blog-backend/database"
+	"kunals-blog-backend/models"
+	"kunals-blog-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+const batchSize = 100
+
+// Sanitize re-runs the current sanitizer policies over stored blogs,
+// trashed ones included, versions, autosaves among them, and comments.
+// Rows are only written when something changed.
+func Sanitize(args []string) error {
+	db := database.GetDB()
+
+	var blogsChanged, versionsChanged, commentsChanged int
+
+	var blogs []models.Blog
+	err := db.Unscoped().FindInBatches(&blogs, batchSize, func(tx *gorm.DB, batch int) error {
+		for i := range blogs {
+			blog := &blogs[i]
+			content, html, preview := blog.Content, blog.ContentHTML, blog.Preview
+			if err := blog.Render(); err != nil {
+				log.Printf("Skipping blog %s: %v", blog.ID, err)
+				continue
+			}
+			if blog.Content == content && blog.ContentHTML == html && blog.Preview == preview {
+				continue
+			}
+			linked, err := blog.LinkContentMedia(db)
+			if err != nil {
+				return err
+			}
+			err = db.Unscoped().Model(blog).
+				Select("content", "content_html", "table_of_contents", "linked_media", "preview", "word_count", "reading_time").
+				UpdateColumns(blog).Error
+			if err != nil {
+				return err
+			}
+			if err := models.RecountMediaRefs(db, linked); err != nil {
+				return err
+			}
+			if err := database.RefreshSearchVector(db, blog.ID); err != nil {
+				log.Printf("Failed to refresh search index for blog %s: %v", blog.ID, err)
+			}
+			blogsChanged++
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	var versions []models.BlogVersion
+	err = db.FindInBatches(&versions, batchSize, func(tx *gorm.DB, batch int) error {
+		for _, version := range versions {
+			clean := models.SanitizeSource(version.ContentFormat, version.Content)
+			if clean == version.Content {
+				continue
+			}
+			linked, err := models.ContentMediaIDs(db, clean)
+			if err != nil {
+				return err
+			}
+			err = db.Model(&version).Select("content", "linked_media").
+				UpdateColumns(models.BlogVersion{Content: clean, LinkedMedia: linked}).Error
+			if err != nil {
+				return err
+			}
+			versionsChanged++
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	var comments []models.Comment
+	err = db.FindInBatches(&comments, batchSize, func(tx *gorm.DB, batch int) error {
+		for _, comment := range comments {
+			clean := utils.SanitizeComment(comment.Content)
+			if clean == comment.Content {
+				continue
+			}
+			if err := db.Model(&models.Comment{}).Where("id = ?", comment.ID).UpdateColumn("content", clean).Error; err != nil {
+				return err
+			}
+			commentsChanged++
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Sanitized %d blogs, %d versions and %d comments", blogsChanged, versionsChanged, commentsChanged)
+	return nil
+}
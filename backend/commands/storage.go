@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"path/filepath"
+
+	"kunals-blog-backend/storage"
+)
+
+// MigrateStorage copies every upload from one storage backend to another,
+// e.g. "server migrate-storage local s3". Files the target already has are
+// skipped, so an interrupted run can be repeated. With --delete the source
+// copy is removed once it has been copied. Switch STORAGE_BACKEND to the
+// target afterwards.
+func MigrateStorage(args []string) error {
+	var names []string
+	deleteSource := false
+	for _, arg := range args {
+		if arg == "--delete" {
+			deleteSource = true
+			continue
+		}
+		names = append(names, arg)
+	}
+	if len(names) != 2 {
+		return fmt.Errorf("usage: migrate-storage <from> <to> [--delete]")
+	}
+	if names[0] == names[1] {
+		return fmt.Errorf("source and target are both %s", names[0])
+	}
+
+	from, err := storage.Open(names[0])
+	if err != nil {
+		return err
+	}
+	to, err := storage.Open(names[1])
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	keys, err := from.List(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	var copied, skipped, failed int
+	for _, key := range keys {
+		exists, err := to.Exists(ctx, key)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			if err := copyFile(ctx, from, to, key); err != nil {
+				log.Printf("Failed to copy %s: %v", key, err)
+				failed++
+				continue
+			}
+			copied++
+		} else {
+			skipped++
+		}
+		if deleteSource {
+			if err := from.Delete(ctx, key); err != nil {
+				log.Printf("Failed to delete %s from %s: %v", key, from.Name(), err)
+			}
+		}
+	}
+
+	log.Printf("Storage migration from %s to %s: %d copied, %d already present, %d failed", from.Name(), to.Name(), copied, skipped, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d files could not be copied", failed)
+	}
+	return nil
+}
+
+func copyFile(ctx context.Context, from, to storage.Storage, key string) error {
+	r, err := from.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return to.Put(ctx, key, bytes.NewReader(data), int64(len(data)), mime.TypeByExtension(filepath.Ext(key)))
+}
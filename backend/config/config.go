@@ -2,25 +2,85 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"time"
 )
 
 type Config struct {
-	Port          string
-	DatabaseURL   string
-	JWTSecret     string
-	AdminUsername string
-	AdminPassword string
-	UploadPath    string
+	Port               string
+	DatabaseURL        string
+	JWTSecret          string
+	AdminUsername      string
+	AdminPassword      string
+	UploadPath         string
+	SchedulerInterval  time.Duration // How often scheduled posts are checked
+	RelatedInterval    time.Duration // How often related posts are fully recomputed
+	TrashRetentionDays int           // Days a deleted blog stays in the trash; 0 keeps it until purged by hand
+
+	// Version retention: every version is kept for VersionKeepAllDays (0
+	// turns pruning off), then one a day up to VersionKeepDailyDays, then
+	// one a week
+	VersionKeepAllDays   int
+	VersionKeepDailyDays int
+	VersionPruneInterval time.Duration
+
+	// Upload storage: "local" keeps files under UploadPath, "s3" in an
+	// S3-compatible bucket (AWS, MinIO)
+	StorageBackend string
+	S3Endpoint     string
+	S3Region       string
+	S3Bucket       string
+	S3AccessKey    string
+	S3SecretKey    string
+	S3UseSSL       bool
+	S3PublicURL    string        // Redirect here instead of signing URLs, e.g. a CDN in front of a public bucket
+	S3URLExpiry    time.Duration // Lifetime of signed URLs
+
+	// HTML sanitizer allowlists. Tags are comma separated; attributes use
+	// "tag:attr|attr" entries where tag "*" means any element.
+	SanitizePostTags     string
+	SanitizePostAttrs    string
+	SanitizePostStyles   string // CSS properties allowed in style attributes (TipTap colour and alignment)
+	SanitizeCommentTags  string
+	SanitizeCommentAttrs string
 }
 
 func GetConfig() *Config {
 	return &Config{
-		Port:          getEnv("PORT", "8080"),
-		DatabaseURL:   getEnv("DATABASE_URL", ""),
-		JWTSecret:     getEnv("JWT_SECRET", "your-super-secret-jwt-key-change-this-in-production"),
-		AdminUsername: getEnv("ADMIN_USERNAME", "admin"),
-		AdminPassword: getEnv("ADMIN_PASSWORD", "admin123"),
-		UploadPath:    getEnv("UPLOAD_PATH", "./uploads"),
+		Port:               getEnv("PORT", "8080"),
+		DatabaseURL:        getEnv("DATABASE_URL", ""),
+		JWTSecret:          getEnv("JWT_SECRET", "your-super-secret-jwt-key-change-this-in-production"),
+		AdminUsername:      getEnv("ADMIN_USERNAME", "admin"),
+		AdminPassword:      getEnv("ADMIN_PASSWORD", "admin123"),
+		UploadPath:         getEnv("UPLOAD_PATH", "./uploads"),
+		SchedulerInterval:  getEnvDuration("SCHEDULER_INTERVAL", 30*time.Second),
+		RelatedInterval:    getEnvDuration("RELATED_INTERVAL", time.Hour),
+		TrashRetentionDays: getEnvInt("TRASH_RETENTION_DAYS", 30),
+
+		VersionKeepAllDays:   getEnvInt("VERSION_KEEP_ALL_DAYS", 30),
+		VersionKeepDailyDays: getEnvInt("VERSION_KEEP_DAILY_DAYS", 180),
+		VersionPruneInterval: getEnvDuration("VERSION_PRUNE_INTERVAL", 24*time.Hour),
+
+		StorageBackend: getEnv("STORAGE_BACKEND", "local"),
+		S3Endpoint:     getEnv("S3_ENDPOINT", "localhost:9000"),
+		S3Region:       getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:       getEnv("S3_BUCKET", "kunals-blog-uploads"),
+		S3AccessKey:    getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:    getEnv("S3_SECRET_KEY", ""),
+		S3UseSSL:       getEnv("S3_USE_SSL", "true") == "true",
+		S3PublicURL:    getEnv("S3_PUBLIC_URL", ""),
+		S3URLExpiry:    getEnvDuration("S3_URL_EXPIRY", time.Hour),
+
+		SanitizePostTags: getEnv("SANITIZE_POST_TAGS",
+			"p,br,hr,h1,h2,h3,h4,h5,h6,strong,b,em,i,u,s,del,ins,sub,sup,mark,small,span,div,"+
+				"blockquote,pre,code,kbd,ul,ol,li,a,img,figure,figcaption,"+
+				"table,thead,tbody,tfoot,tr,th,td,input"),
+		SanitizePostAttrs: getEnv("SANITIZE_POST_ATTRS",
+			"*:id|class|title,a:href|rel|target,img:src|alt|width|height|srcset|sizes|loading,"+
+				"th:align|colspan|rowspan,td:align|colspan|rowspan,ol:start,input:type|checked|disabled"),
+		SanitizePostStyles:   getEnv("SANITIZE_POST_STYLES", "color,background-color,text-align"),
+		SanitizeCommentTags:  getEnv("SANITIZE_COMMENT_TAGS", "p,br,strong,b,em,i,code,pre,blockquote,a"),
+		SanitizeCommentAttrs: getEnv("SANITIZE_COMMENT_ATTRS", "a:href"),
 	}
 }
 
@@ -30,3 +90,23 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvInt reads a non-negative integer
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if n, err := strconv.Atoi(value); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return defaultValue
+}
+
+// getEnvDuration reads a Go duration such as "30s" or "1h"
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if d, err := time.ParseDuration(value); err == nil && d > 0 {
+			return d
+		}
+	}
+	return defaultValue
+}
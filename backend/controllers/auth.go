@@ -57,7 +57,7 @@ func Signup(c *gin.Context) {
 		return
 	}
 
-	user := models.User{Username: req.Username, Password: string(hashed), IsAdmin: false}
+	user := models.User{Username: req.Username, Password: string(hashed), IsAdmin: false, Roles: []string{models.RoleReader}}
 	if err := db.Create(&user).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
 		return
@@ -95,6 +95,7 @@ func Login(c *gin.Context) {
 				Username: cfg.AdminUsername,
 				Password: string(hashedPassword),
 				IsAdmin:  true,
+				Roles:    []string{models.RoleAdmin},
 			}
 
 			if err := db.Create(&adminUser).Error; err != nil {
@@ -104,7 +105,7 @@ func Login(c *gin.Context) {
 		}
 
 		// Generate JWT token
-		token, err := utils.GenerateJWT(adminUser.ID, adminUser.Username, adminUser.IsAdmin)
+		token, err := utils.GenerateJWT(adminUser.ID, adminUser.Username, adminUser.IsAdmin, adminUser.EffectiveRoles())
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 			return
@@ -135,7 +136,7 @@ func Login(c *gin.Context) {
 	}
 
 	// Generate JWT token
-	token, err := utils.GenerateJWT(user.ID, user.Username, user.IsAdmin)
+	token, err := utils.GenerateJWT(user.ID, user.Username, user.IsAdmin, user.EffectiveRoles())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
@@ -160,11 +161,15 @@ func ValidateToken(c *gin.Context) {
 
 	username, _ := c.Get("username")
 	isAdmin, _ := c.Get("is_admin")
+	rolesVal, _ := c.Get("roles")
+	roles, _ := rolesVal.([]string)
 
 	c.JSON(http.StatusOK, gin.H{
-		"valid":    true,
-		"user_id":  userID,
-		"username": username,
-		"is_admin": isAdmin,
+		"valid":       true,
+		"user_id":     userID,
+		"username":    username,
+		"is_admin":    isAdmin,
+		"roles":       roles,
+		"permissions": models.PermissionsFor(roles),
 	})
 }
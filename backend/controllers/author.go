@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"kunals-blog-backend/database"
+	"kunals-blog-backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UpdateProfileRequest struct {
+	DisplayName string `json:"display_name"`
+	Bio         string `json:"bio"`
+	AvatarURL   string `json:"avatar_url"`
+}
+
+type authorSummary struct {
+	models.Author
+	PostCount int64 `json:"post_count"`
+}
+
+// ListAuthors returns everyone with at least one published post
+func ListAuthors(c *gin.Context) {
+	db := database.GetDB()
+
+	var authors []authorSummary
+	err := db.Table("users").
+		Select("users.id, users.username, users.display_name, users.bio, users.avatar_url, COUNT(blogs.id) AS post_count").
+		Joins("JOIN blogs ON blogs.author_id = users.id AND blogs.is_published = true AND blogs.deleted_at IS NULL").
+		Group("users.id").
+		Order("post_count DESC").Order("users.username ASC").
+		Scan(&authors).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch authors"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"authors": authors})
+}
+
+// GetAuthor returns an author's profile and their published posts
+func GetAuthor(c *gin.Context) {
+	db := database.GetDB()
+
+	var author models.Author
+	if err := db.First(&author, "username = ?", c.Param("username")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Author not found"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 || limit > 50 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	query := db.Model(&models.Blog{}).Where("author_id = ? AND is_published = ?", author.ID, true)
+
+	var total int64
+	query.Count(&total)
+	// Readers only have a profile page once they have published something
+	if total == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Author not found"})
+		return
+	}
+
+	var blogs []models.Blog
+	err := query.Preload("Author").Preload("Tags").Preload("Categories").
+		Order("COALESCE(published_at, created_at) DESC").
+		Limit(limit).Offset(offset).
+		Find(&blogs).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch blogs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"author": authorSummary{Author: author, PostCount: total},
+		"blogs":  blogs,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// UpdateProfile lets the logged in user edit their public author profile
+func UpdateProfile(c *gin.Context) {
+	var req UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	db := database.GetDB()
+
+	var user models.User
+	if err := db.First(&user, "id = ?", userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	user.DisplayName = req.DisplayName
+	user.Bio = req.Bio
+	user.AvatarURL = req.AvatarURL
+	if err := db.Save(&user).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
+		return
+	}
+
+	user.Password = ""
+	c.JSON(http.StatusOK, gin.H{"message": "Profile updated successfully", "user": user})
+}
@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"kunals-blog-backend/database"
+	"kunals-blog-backend/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/clause"
+)
+
+type AutosaveRequest struct {
+	Title         string   `json:"title"`
+	Content       string   `json:"content"`
+	ContentFormat string   `json:"content_format"`
+	Language      string   `json:"language"`
+	MediaIDs      []string `json:"media_ids"`
+}
+
+// Autosave stores the editor's work in progress. Each editor has a single
+// working draft per blog that every call overwrites; only an explicit save
+// through UpdateBlog creates a named version.
+func Autosave(c *gin.Context) {
+	var req AutosaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	var blog models.Blog
+	if err := db.First(&blog, "id = ?", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		return
+	}
+	if !canEditBlog(c, &blog) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit your own posts"})
+		return
+	}
+
+	mediaIDs, err := resolveMedia(db, req.MediaIDs)
+	if err != nil {
+		if err == errUnknownMedia {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown media"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load media"})
+		return
+	}
+	draft, err := draftVersion(db, &blog, req.Title, req.Content, req.ContentFormat, req.Language, mediaIDs)
+	if err != nil {
+		if errors.Is(err, models.ErrUnknownContentFormat) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "content_format must be html or markdown"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to autosave"})
+		return
+	}
+	baseID, err := currentVersionID(db, &blog)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to autosave"})
+		return
+	}
+	uid := currentUserID(c)
+	draft.BaseVersionID = &baseID
+	draft.AuthorID = &uid
+	draft.Status = models.VersionStatusAutosave
+
+	// The draft keeps the base it started from so it can still be merged
+	err = db.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "blog_id"}, {Name: "author_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "status = 'autosave'"}}},
+		DoUpdates:   clause.AssignmentColumns([]string{"title", "content", "content_format", "language", "media_ids", "linked_media", "updated_at"}),
+	}).Create(&draft).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to autosave"})
+		return
+	}
+	// On conflict the row kept its own ID, so read it back
+	db.First(&draft, "blog_id = ? AND author_id = ? AND status = ?", blog.ID, uid, models.VersionStatusAutosave)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Draft autosaved", "version": draft})
+}
+
+// GetAutosave returns the current editor's working draft of a blog
+func GetAutosave(c *gin.Context) {
+	db := database.GetDB()
+	var draft models.BlogVersion
+	err := db.First(&draft, "blog_id = ? AND author_id = ? AND status = ?", c.Param("id"), currentUserID(c), models.VersionStatusAutosave).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No autosaved draft"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"version": draft})
+}
+
+// DiscardAutosave throws away the current editor's working draft
+func DiscardAutosave(c *gin.Context) {
+	db := database.GetDB()
+	result := db.Where("blog_id = ? AND author_id = ? AND status = ?", c.Param("id"), currentUserID(c), models.VersionStatusAutosave).
+		Delete(&models.BlogVersion{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to discard draft"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No autosaved draft"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Draft discarded"})
+}
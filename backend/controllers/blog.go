@@ -1,32 +1,74 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"kunals-blog-backend/database"
+	"kunals-blog-backend/jobs"
 	"kunals-blog-backend/models"
 	"kunals-blog-backend/utils"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type CreateBlogRequest struct {
-	Title      string   `json:"title" binding:"required"`
-	Content    string   `json:"content" binding:"required"`
-	Language   string   `json:"language"`
-	Images     []string `json:"images"`
-	CustomDate string   `json:"custom_date"`
+	Title         string   `json:"title" binding:"required"`
+	Slug          string   `json:"slug"`
+	Content       string   `json:"content" binding:"required"`
+	ContentFormat string   `json:"content_format"` // html (default) or markdown
+	Excerpt       string   `json:"excerpt"`        // Optional manual preview
+	Language      string   `json:"language"`
+	MediaIDs      []string `json:"media_ids"` // Images from the media library, in order
+	CustomDate    string   `json:"custom_date"`
+	Tags          []string `json:"tags"`           // Tag names, created if missing
+	Categories    []string `json:"categories"`     // Category slugs
+	TranslationOf string   `json:"translation_of"` // ID of the post this one translates
 }
 
 type UpdateBlogRequest struct {
-	Title      string   `json:"title"`
-	Content    string   `json:"content"`
-	Language   string   `json:"language"`
-	Images     []string `json:"images"`
-	CustomDate string   `json:"custom_date"`
+	Title         string    `json:"title"`
+	Slug          string    `json:"slug"`
+	Content       string    `json:"content"`
+	ContentFormat string    `json:"content_format"`
+	Excerpt       *string   `json:"excerpt"` // Empty string clears the manual excerpt
+	Language      string    `json:"language"`
+	MediaIDs      []string  `json:"media_ids"` // Replaces the images when present
+	CustomDate    string    `json:"custom_date"`
+	Tags          *[]string `json:"tags"`          // Replaces all tags when present
+	Categories    *[]string `json:"categories"`    // Replaces all categories when present
+	Message       string    `json:"message"`       // Optional note stored on the version
+	BaseRevision  *int      `json:"base_revision"` // Alternative to the If-Match header
+}
+
+type PublishRequest struct {
+	PublishAt string `json:"publish_at"`
+}
+
+// parseDateTime accepts both YYYY-MM-DDTHH:mm and RFC3339, nil if neither
+func parseDateTime(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	if t, err := time.Parse("2006-01-02T15:04", value); err == nil {
+		return &t
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t
+	}
+	return nil
+}
+
+// cleanExcerpt reduces a manual excerpt to plain text; false if too long
+func cleanExcerpt(excerpt string) (string, bool) {
+	excerpt = utils.PlainText(excerpt)
+	return excerpt, utf8.RuneCountInString(excerpt) <= 500
 }
 
 // CreateBlog creates a new blog post (draft by default)
@@ -37,45 +79,101 @@ func CreateBlog(c *gin.Context) {
 		return
 	}
 
-	db := database.GetDB()
-
-	// Generate preview from content (first 200 characters)
-	preview := req.Content
-	if len(preview) > 200 {
-		preview = preview[:200] + "..."
+	format, err := models.NormalizeContentFormat(req.ContentFormat)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "content_format must be html or markdown"})
+		return
 	}
 
-	// Convert images slice to JSON string
-	imagesJSON := ""
-	if len(req.Images) > 0 {
-		imagesJSON = strings.Join(req.Images, ",")
-	}
+	db := database.GetDB()
 
-	var customDatePtr *time.Time
-	if strings.TrimSpace(req.CustomDate) != "" {
-		// Accept both YYYY-MM-DDTHH:mm and RFC3339
-		if t, err := time.Parse("2006-01-02T15:04", req.CustomDate); err == nil {
-			customDatePtr = &t
-		} else if t2, err2 := time.Parse(time.RFC3339, req.CustomDate); err2 == nil {
-			customDatePtr = &t2
-		}
+	customDatePtr := parseDateTime(req.CustomDate)
+
+	excerpt, ok := cleanExcerpt(req.Excerpt)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Excerpt must be at most 500 characters"})
+		return
 	}
 
 	blog := models.Blog{
-		Title:      req.Title,
-		Content:    req.Content,
-		Preview:    preview,
-		Language:   req.Language,
-		Images:     imagesJSON,
-		CustomDate: customDatePtr,
+		Title:         req.Title,
+		Content:       req.Content,
+		ContentFormat: format,
+		Excerpt:       excerpt,
+		Language:      req.Language,
+		CustomDate:    customDatePtr,
 	}
 	if blog.Language == "" {
 		blog.Language = "english"
 	}
-	if err := db.Create(&blog).Error; err != nil {
+	// Preview is generated from the rendered text
+	if err := blog.Render(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render content"})
+		return
+	}
+	if strings.TrimSpace(req.Slug) != "" {
+		blog.Slug = models.UniqueBlogSlug(db, req.Slug, "")
+		blog.SlugLocked = true
+	}
+	categories, err := resolveCategories(db, req.Categories)
+	if err != nil {
+		if err == errUnknownCategory {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown category"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load categories"})
+		return
+	}
+	tags, err := resolveTags(db, req.Tags)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save tags"})
+		return
+	}
+	mediaIDs, err := resolveMedia(db, req.MediaIDs)
+	if err != nil {
+		if err == errUnknownMedia {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown media"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load media"})
+		return
+	}
+	blog.Tags = tags
+	blog.Categories = categories
+	for i, id := range mediaIDs {
+		blog.Images = append(blog.Images, models.BlogImage{MediaID: id, Position: i})
+	}
+	if req.TranslationOf != "" {
+		if err := joinTranslationGroup(db, &blog, req.TranslationOf); err != nil {
+			switch err {
+			case errTranslationSource:
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Original post for translation not found"})
+			case errTranslationExists:
+				c.JSON(http.StatusConflict, gin.H{"error": "A translation in this language already exists"})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to link translation"})
+			}
+			return
+		}
+	}
+	if uid := currentUserID(c); uid != "" {
+		blog.AuthorID = &uid
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		linked, err := blog.LinkContentMedia(tx)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(&blog).Error; err != nil {
+			return err
+		}
+		return models.RecountMediaRefs(tx, append(mediaIDs, linked...))
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create blog"})
 		return
 	}
+	refreshSearchIndex(db, blog.ID)
 
 	c.JSON(http.StatusCreated, gin.H{"message": "Blog created successfully", "blog": blog})
 }
@@ -89,25 +187,86 @@ func GetBlogs(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 	publishedOnly := c.DefaultQuery("published_only", "true") == "true"
 	language := c.Query("language")
+	tagSlugs := queryList(c, "tag")
+	matchAllTags := c.DefaultQuery("tag_match", "any") == "all"
+	category := c.Query("category")
+	author := c.Query("author") // user ID or username
+	collapse := c.Query("collapse_translations") == "true"
+	pinnedFirst := c.Query("pinned_first") == "true"
 	sortBy := c.DefaultQuery("sort_by", "recent") // recent | most_commented | most_liked | most_viewed
 
 	offset := (page - 1) * limit
 
-	// Build query
-	query := db.Model(&models.Blog{})
+	// Spellings of one tag count once, or tag_match=all could never match
+	if len(tagSlugs) > 0 {
+		unique := make([]string, 0, len(tagSlugs))
+		seen := map[string]bool{}
+		for _, tag := range tagSlugs {
+			if slug := utils.Slugify(tag); !seen[slug] {
+				seen[slug] = true
+				unique = append(unique, slug)
+			}
+		}
+		tagSlugs = unique
+	}
 
-	if publishedOnly {
-		query = query.Where("is_published = ?", true)
+	filter := func(query *gorm.DB) *gorm.DB {
+		if publishedOnly {
+			query = query.Where("is_published = ?", true)
+		}
+
+		if language != "" {
+			query = query.Where("language = ?", language)
+		}
+
+		if len(tagSlugs) > 0 {
+			tagged := db.Table("blog_tags").
+				Select("blog_tags.blog_id").
+				Joins("JOIN tags ON tags.id = blog_tags.tag_id").
+				Where("tags.slug IN ?", tagSlugs)
+			if matchAllTags {
+				tagged = tagged.Group("blog_tags.blog_id").Having("COUNT(DISTINCT tags.id) = ?", len(tagSlugs))
+			}
+			query = query.Where("id IN (?)", tagged)
+		}
+
+		if category != "" {
+			inCategory := db.Table("blog_categories").
+				Select("blog_categories.blog_id").
+				Joins("JOIN categories ON categories.id = blog_categories.category_id").
+				Where("categories.slug = ?", utils.Slugify(category))
+			query = query.Where("id IN (?)", inCategory)
+		}
+
+		if author != "" {
+			byAuthor := db.Table("users").Select("id").Where("id = ? OR username = ?", author, author)
+			query = query.Where("author_id IN (?)", byAuthor)
+		}
+		return query
 	}
 
-	if language != "" {
-		query = query.Where("language = ?", language)
+	// Build query
+	query := db.Model(&models.Blog{}).Scopes(filter)
+
+	// Show each translated post once, in the reader's preferred language.
+	// The pick is made among the posts that pass the filters, so a group
+	// isn't lost when only another language's post matches.
+	if collapse {
+		c.Header("Vary", "Accept-Language")
+		preferred := utils.PreferredLanguages(c.GetHeader("Accept-Language"))
+		query = query.Where("id IN (?)", pickTranslations(db.Model(&models.Blog{}).Scopes(filter), preferred))
 	}
 
 	// Get total count
 	var total int64
 	query.Count(&total)
 
+	// Pinned posts go above everything, whatever the sort
+	if pinnedFirst {
+		// Lapsed pins keep their pin_order, so it only counts while active
+		query = query.Order("(" + pinnedActiveSQL + ") DESC").Order("CASE WHEN " + pinnedActiveSQL + " THEN pin_order END ASC")
+	}
+
 	// Sorting: prefer published_at for public, updated_at for admin
 	switch sortBy {
 	case "most_commented":
@@ -129,21 +288,13 @@ func GetBlogs(c *gin.Context) {
 
 	// Get blogs with pagination
 	var blogs []models.Blog
-	result := query.Limit(limit).Offset(offset).Find(&blogs)
+	result := query.Preload("Author").Preload("Tags").Preload("Categories").Scopes(models.WithImages).Limit(limit).Offset(offset).Find(&blogs)
 
 	if result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch blogs"})
 		return
 	}
 
-	// Convert images string back to array for each blog
-	for i := range blogs {
-		if blogs[i].Images != "" {
-			// For simplicity, we'll keep it as string in response
-			// Frontend can split by comma if needed
-		}
-	}
-
 	c.JSON(http.StatusOK, gin.H{
 		"blogs": blogs,
 		"pagination": gin.H{
@@ -155,13 +306,38 @@ func GetBlogs(c *gin.Context) {
 	})
 }
 
-// GetBlog returns a single blog by ID and records a view
+// GetBlog returns a single published blog by ID and records a view
 func GetBlog(c *gin.Context) {
-	blogID := c.Param("id")
+	showBlog(c, c.Param("id"))
+}
+
+// GetAdminBlog returns a blog by ID for editing, drafts and scheduled posts
+// included. No view is recorded.
+func GetAdminBlog(c *gin.Context) {
 	db := database.GetDB()
 
 	var blog models.Blog
-	result := db.Preload("Comments").Preload("Likes").First(&blog, "id = ?", blogID)
+	if err := db.Preload("Author").Preload("Tags").Preload("Categories").Scopes(models.WithImages).First(&blog, "id = ?", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		return
+	}
+	if !canEditBlog(c, &blog) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit your own posts"})
+		return
+	}
+
+	setRevisionETag(c, &blog)
+	c.JSON(http.StatusOK, gin.H{"blog": blog, "translations": blogTranslations(db, &blog)})
+}
+
+// showBlog writes the published blog with its comments and likes, recording
+// a view. Drafts and scheduled posts are only reachable through the admin API.
+func showBlog(c *gin.Context, blogID string) {
+	db := database.GetDB()
+
+	var blog models.Blog
+	result := db.Preload("Author").Preload("Comments").Preload("Likes").Preload("Tags").Preload("Categories").Scopes(models.WithImages).
+		First(&blog, "id = ? AND is_published = ?", blogID, true)
 
 	if result.Error != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
@@ -195,11 +371,47 @@ func GetBlog(c *gin.Context) {
 		}
 	}
 
-	// load versions
-	var versions []models.BlogVersion
-	_ = db.Where("blog_id = ?", blogID).Order("created_at DESC").Find(&versions)
+	setRevisionETag(c, &blog)
+	c.JSON(http.StatusOK, gin.H{"blog": blog, "translations": blogTranslations(db, &blog)})
+}
 
-	c.JSON(http.StatusOK, gin.H{"blog": blog, "versions": versions})
+// draftVersion builds a version of the blog from requested content, keeping
+// the blog's current value for anything left empty. A nil mediaIDs keeps
+// the current images.
+func draftVersion(db *gorm.DB, blog *models.Blog, title, content, format, language string, mediaIDs []string) (models.BlogVersion, error) {
+	version := models.BlogVersion{
+		BlogID:        blog.ID,
+		Title:         blog.Title,
+		Content:       blog.Content,
+		ContentFormat: blog.ContentFormat,
+		Language:      blog.Language,
+		MediaIDs:      mediaIDs,
+	}
+	if title != "" {
+		version.Title = title
+	}
+	if content != "" {
+		version.Content = content
+	}
+	if format != "" {
+		normalized, err := models.NormalizeContentFormat(format)
+		if err != nil {
+			return version, err
+		}
+		version.ContentFormat = normalized
+	}
+	if language != "" {
+		version.Language = language
+	}
+	if mediaIDs == nil {
+		current, err := models.BlogMediaIDs(db, blog.ID)
+		if err != nil {
+			return version, err
+		}
+		version.MediaIDs = current
+	}
+	version.Content = models.SanitizeSource(version.ContentFormat, version.Content)
+	return version, nil
 }
 
 // UpdateBlog updates and snapshots a version before saving
@@ -216,55 +428,197 @@ func UpdateBlog(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
 		return
 	}
-	// Build a pending version from the requested changes or current values
-	title := blog.Title
-	content := blog.Content
-	language := blog.Language
-	images := blog.Images
-	if req.Title != "" {
-		title = req.Title
+	if !canEditBlog(c, &blog) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit your own posts"})
+		return
+	}
+	base, ok := baseRevision(c, req.BaseRevision)
+	if !ok {
+		return
+	}
+	if base != blog.Revision {
+		revisionConflict(c, db, blog.ID)
+		return
 	}
-	if req.Content != "" {
-		content = req.Content
+	message := strings.TrimSpace(req.Message)
+	if utf8.RuneCountInString(message) > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Message must be at most 500 characters"})
+		return
 	}
-	if req.Language != "" {
-		language = req.Language
+	mediaIDs, err := resolveMedia(db, req.MediaIDs)
+	if err != nil {
+		if err == errUnknownMedia {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown media"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load media"})
+		return
 	}
-	if len(req.Images) > 0 {
-		images = strings.Join(req.Images, ",")
+	// Build a named pending version from the requested changes or current values
+	version, err := draftVersion(db, &blog, req.Title, req.Content, req.ContentFormat, req.Language, mediaIDs)
+	if err != nil {
+		if errors.Is(err, models.ErrUnknownContentFormat) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "content_format must be html or markdown"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create version"})
+		return
 	}
-	version := models.BlogVersion{BlogID: blog.ID, Title: title, Content: content, Language: language, Images: images, IsPending: true}
-	if err := db.Create(&version).Error; err != nil {
+	baseID, err := currentVersionID(db, &blog)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create version"})
 		return
 	}
-	// Update metadata on live blog (language/custom_date)
+	uid := currentUserID(c)
+	version.BaseVersionID = &baseID
+	version.Message = message
+	version.Status = models.VersionStatusPending
+	if uid != "" {
+		version.AuthorID = &uid
+	}
+	// Update metadata on live blog (language/custom_date). Everything is
+	// checked before anything is written.
 	metaUpdated := false
 	if req.Language != "" && blog.Language != req.Language {
 		blog.Language = req.Language
 		metaUpdated = true
 	}
-	if strings.TrimSpace(req.CustomDate) != "" {
-		if t, err := time.Parse("2006-01-02T15:04", req.CustomDate); err == nil {
-			blog.CustomDate = &t
-			metaUpdated = true
-		} else if t2, err2 := time.Parse(time.RFC3339, req.CustomDate); err2 == nil {
-			blog.CustomDate = &t2
-			metaUpdated = true
+	if req.Excerpt != nil {
+		excerpt, ok := cleanExcerpt(*req.Excerpt)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Excerpt must be at most 500 characters"})
+			return
 		}
+		blog.Excerpt = excerpt
+		blog.RefreshTextStats()
+		metaUpdated = true
 	}
-	if metaUpdated {
-		if blog.IsPublished && blog.CustomDate != nil {
+	slug := strings.TrimSpace(req.Slug)
+	if slug != "" {
+		metaUpdated = true
+	}
+	scheduleMoved := false
+	if t := parseDateTime(req.CustomDate); t != nil {
+		blog.CustomDate = t
+		metaUpdated = true
+		// keep a pending schedule in step with the new date
+		if blog.ScheduledAt != nil && t.After(time.Now()) {
+			blog.ScheduledAt = t
+			scheduleMoved = true
+		}
+	}
+	var categories []models.Category
+	if req.Categories != nil {
+		var err error
+		categories, err = resolveCategories(db, *req.Categories)
+		if err != nil {
+			if err == errUnknownCategory {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown category"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load categories"})
+			return
+		}
+		metaUpdated = true
+	}
+	if req.Tags != nil {
+		metaUpdated = true
+	}
+	// A published post follows its custom date; a future one takes it off
+	// until then, the same as publishing with that date
+	publishMoved, unpublished := false, false
+	if metaUpdated && blog.IsPublished && blog.CustomDate != nil {
+		if blog.CustomDate.After(time.Now()) {
+			if !hasPermission(c, models.PermPublish) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Only publishers can move a published post to a future date"})
+				return
+			}
+			blog.IsPublished = false
+			blog.PublishedAt = nil
+			blog.ScheduledAt = blog.CustomDate
+			unpublished = true
+		} else {
 			blog.PublishedAt = blog.CustomDate
 		}
-		if err := db.Save(&blog).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update blog metadata"})
+		publishMoved = true
+	}
+
+	// The version, slug change and metadata are saved together, so a
+	// conflict or failure leaves none of them behind
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&version).Error; err != nil {
+			return err
+		}
+		if metaUpdated {
+			if slug != "" {
+				if err := setBlogSlug(tx, &blog, slug); err != nil {
+					return err
+				}
+				blog.SlugLocked = true
+			}
+			if err := blog.SaveRevision(tx, base); err != nil {
+				return err
+			}
+			if publishMoved || scheduleMoved {
+				if err := savePublishState(tx, &blog); err != nil {
+					return err
+				}
+			}
+			if req.Categories != nil {
+				if err := tx.Model(&blog).Association("Categories").Replace(categories); err != nil {
+					return err
+				}
+			}
+			if req.Tags != nil {
+				tags, err := resolveTags(tx, *req.Tags)
+				if err != nil {
+					return err
+				}
+				if err := tx.Model(&blog).Association("Tags").Replace(tags); err != nil {
+					return err
+				}
+			}
+		}
+		// The named version supersedes this editor's working draft. It goes
+		// last so a conflict or failure above keeps the draft.
+		if uid != "" {
+			return tx.Where("blog_id = ? AND author_id = ? AND status = ?", blog.ID, uid, models.VersionStatusAutosave).Delete(&models.BlogVersion{}).Error
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, models.ErrRevisionConflict) {
+			revisionConflict(c, db, blog.ID)
 			return
 		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update blog"})
+		return
+	}
+	if metaUpdated {
+		refreshSearchIndex(db, blog.ID)
+	}
+	if unpublished {
+		jobs.RequestRelatedRefresh()
 	}
+	setRevisionETag(c, &blog)
 	c.JSON(http.StatusOK, gin.H{"message": "Draft version created", "version": version, "blog": blog})
 }
 
+// savePublishState writes only the publishing columns of blog and reloads
+// it, so content applied since it was read isn't reverted
+func savePublishState(db *gorm.DB, blog *models.Blog) error {
+	err := db.Model(blog).UpdateColumns(map[string]interface{}{
+		"is_published": blog.IsPublished,
+		"published_at": blog.PublishedAt,
+		"scheduled_at": blog.ScheduledAt,
+		"updated_at":   time.Now(),
+	}).Error
+	if err != nil {
+		return err
+	}
+	return db.First(blog, "id = ?", blog.ID).Error
+}
+
 // PublishBlog publishes a draft blog
 func PublishBlog(c *gin.Context) {
 	blogID := c.Param("id")
@@ -276,19 +630,54 @@ func PublishBlog(c *gin.Context) {
 		return
 	}
 
+	// An explicit publish_at wins over the blog's custom date
+	var req PublishRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	publishAt := blog.CustomDate
+	if strings.TrimSpace(req.PublishAt) != "" {
+		if publishAt = parseDateTime(req.PublishAt); publishAt == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid publish_at date"})
+			return
+		}
+	}
+
+	// A future date schedules the blog instead of publishing it now
+	if publishAt != nil && publishAt.After(time.Now()) {
+		blog.IsPublished = false
+		blog.PublishedAt = nil
+		blog.ScheduledAt = publishAt
+		if err := savePublishState(db, &blog); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to schedule blog"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Blog scheduled for publishing",
+			"blog":    blog,
+		})
+		return
+	}
+
 	blog.IsPublished = true
-	if blog.CustomDate != nil {
-		blog.PublishedAt = blog.CustomDate
+	blog.ScheduledAt = nil
+	if publishAt != nil {
+		blog.PublishedAt = publishAt
 	} else {
 		now := time.Now()
 		blog.PublishedAt = &now
 	}
 
-	if err := db.Save(&blog).Error; err != nil {
+	if err := savePublishState(db, &blog); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to publish blog"})
 		return
 	}
 
+	jobs.RequestRelatedRefresh()
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Blog published successfully",
 		"blog":    blog,
@@ -308,12 +697,15 @@ func UnpublishBlog(c *gin.Context) {
 
 	blog.IsPublished = false
 	blog.PublishedAt = nil
+	blog.ScheduledAt = nil
 
-	if err := db.Save(&blog).Error; err != nil {
+	if err := savePublishState(db, &blog); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unpublish blog"})
 		return
 	}
 
+	jobs.RequestRelatedRefresh()
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Blog unpublished successfully",
 		"blog":    blog,
@@ -331,44 +723,101 @@ func ApplyVersion(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
 		return
 	}
+	if !canEditBlog(c, &blog) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit your own posts"})
+		return
+	}
 	var version models.BlogVersion
 	if err := db.First(&version, "id = ? AND blog_id = ?", versionID, blogID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Version not found"})
 		return
 	}
-	// snapshot current live into versions (history)
-	_ = db.Create(&models.BlogVersion{BlogID: blog.ID, Title: blog.Title, Content: blog.Content, Language: blog.Language, Images: blog.Images, IsPending: false})
-	// apply version fields to blog (live)
-	blog.Title = version.Title
-	blog.Content = version.Content
-	blog.Language = version.Language
-	blog.Images = version.Images
-	// recalc preview
-	preview := version.Content
-	if len(preview) > 200 {
-		preview = preview[:200] + "..."
-	}
-	blog.Preview = preview
-	if err := db.Save(&blog).Error; err != nil {
+	if version.Status != models.VersionStatusPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only pending versions can be applied"})
+		return
+	}
+	base, ok := applyBaseRevision(c)
+	if !ok {
+		return
+	}
+	if err := applyToLive(db, &blog, &version, base); err != nil {
+		if errors.Is(err, models.ErrRevisionConflict) {
+			revisionConflict(c, db, blog.ID)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply version"})
 		return
 	}
-	// mark version as not pending (applied)
-	version.IsPending = false
+	// mark version as applied
+	version.Status = models.VersionStatusApplied
 	_ = db.Save(&version)
+	setRevisionETag(c, &blog)
 	c.JSON(http.StatusOK, gin.H{"message": "Version applied to draft", "blog": blog})
 }
 
-// DeleteBlog deletes a blog
+// applyToLive copies the version's content onto the live blog, which then
+// points at the version. Live content no version holds yet is snapshotted
+// into history first. Nothing is written unless the blog is still at
+// revision base.
+func applyToLive(db *gorm.DB, blog *models.Blog, version *models.BlogVersion, base int) error {
+	if blog.Revision != base {
+		return models.ErrRevisionConflict
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// make sure the content being replaced is kept as a version
+		if _, err := currentVersionID(tx, blog); err != nil {
+			return err
+		}
+		// follow the new title unless the admin chose the slug by hand
+		if version.Title != blog.Title && !blog.SlugLocked {
+			if err := setBlogSlug(tx, blog, version.Title); err != nil {
+				return err
+			}
+		}
+		// apply version fields to blog (live)
+		blog.Title = version.Title
+		blog.Content = version.Content
+		blog.ContentFormat = version.ContentFormat
+		blog.Language = version.Language
+		blog.CurrentVersionID = &version.ID
+		now := time.Now()
+		version.AppliedAt = &now
+		if err := tx.Model(version).UpdateColumn("applied_at", now).Error; err != nil {
+			return err
+		}
+		if err := models.SetBlogImages(tx, blog.ID, version.MediaIDs); err != nil {
+			return err
+		}
+		// re-render html and preview
+		if err := blog.Render(); err != nil {
+			return err
+		}
+		linked, err := blog.LinkContentMedia(tx)
+		if err != nil {
+			return err
+		}
+		if err := blog.SaveRevision(tx, base); err != nil {
+			return err
+		}
+		return models.RecountMediaRefs(tx, linked)
+	})
+	if err != nil {
+		return err
+	}
+	refreshSearchIndex(db, blog.ID)
+	if blog.IsPublished {
+		jobs.RequestRelatedRefresh()
+	}
+	return nil
+}
+
+// DeleteBlog moves a blog to the trash
 func DeleteBlog(c *gin.Context) {
 	blogID := c.Param("id")
 	db := database.GetDB()
 
-	// Delete associated comments and likes first
-	db.Where("blog_id = ?", blogID).Delete(&models.Comment{})
-	db.Where("blog_id = ?", blogID).Delete(&models.Like{})
-
-	// Delete the blog
+	// Soft delete: the blog moves to the trash and keeps its comments,
+	// likes, views and versions until it is purged
 	result := db.Delete(&models.Blog{}, "id = ?", blogID)
 	if result.Error != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete blog"})
@@ -380,5 +829,7 @@ func DeleteBlog(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Blog deleted successfully"})
+	jobs.RequestRelatedRefresh()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Blog moved to trash"})
 }
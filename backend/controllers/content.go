@@ -0,0 +1,37 @@
+package controllers
+
+import (
+	"net/http"
+
+	"kunals-blog-backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RenderRequest struct {
+	Content       string `json:"content"`
+	ContentFormat string `json:"content_format"`
+}
+
+// RenderContent renders a draft so the editor can show a live preview
+func RenderContent(c *gin.Context) {
+	var req RenderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	format, err := models.NormalizeContentFormat(req.ContentFormat)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "content_format must be html or markdown"})
+		return
+	}
+
+	rendered, err := models.RenderContent(format, req.Content)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render content"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"content_format": format, "content_html": rendered})
+}
@@ -0,0 +1,203 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"kunals-blog-backend/database"
+	"kunals-blog-backend/models"
+	"kunals-blog-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// liveRevision names the blog's current content in diff requests
+const liveRevision = "live"
+
+// revision is one side of a diff: a stored version or the live blog
+type revision struct {
+	ID            string    `json:"id"`
+	Title         string    `json:"title"`
+	Language      string    `json:"language"`
+	ContentFormat string    `json:"content_format"`
+	CreatedAt     time.Time `json:"created_at"`
+	content       string
+	images        []string // Media IDs
+}
+
+type fieldChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// loadRevision resolves "live" or a version ID belonging to the blog
+func loadRevision(db *gorm.DB, blog *models.Blog, ref string) (*revision, error) {
+	if ref == liveRevision {
+		images, err := models.BlogMediaIDs(db, blog.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &revision{
+			ID:            liveRevision,
+			Title:         blog.Title,
+			Language:      blog.Language,
+			ContentFormat: blog.ContentFormat,
+			CreatedAt:     blog.UpdatedAt,
+			content:       blog.Content,
+			images:        images,
+		}, nil
+	}
+	var version models.BlogVersion
+	if err := db.First(&version, "id = ? AND blog_id = ?", ref, blog.ID).Error; err != nil {
+		return nil, err
+	}
+	return &revision{
+		ID:            version.ID,
+		Title:         version.Title,
+		Language:      version.Language,
+		ContentFormat: version.ContentFormat,
+		CreatedAt:     version.CreatedAt,
+		content:       version.Content,
+		images:        version.MediaIDs,
+	}, nil
+}
+
+// imageChanges lists media present on only one side
+func imageChanges(from, to []string) (added, removed []string) {
+	inFrom := map[string]bool{}
+	for _, img := range from {
+		inFrom[img] = true
+	}
+	inTo := map[string]bool{}
+	for _, img := range to {
+		inTo[img] = true
+		if !inFrom[img] {
+			added = append(added, img)
+		}
+	}
+	for _, img := range from {
+		if !inTo[img] {
+			removed = append(removed, img)
+		}
+	}
+	return added, removed
+}
+
+// DiffVersions compares two revisions of a blog. "from" and "to" take a
+// version ID or "live"; "from" defaults to the live blog so a pending
+// version can be reviewed before it is applied.
+func DiffVersions(c *gin.Context) {
+	fromRef := c.DefaultQuery("from", liveRevision)
+	toRef := c.Query("to")
+	if toRef == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to is required"})
+		return
+	}
+	contextSize, _ := strconv.Atoi(c.DefaultQuery("context", "3"))
+	if contextSize < 0 || contextSize > 50 {
+		contextSize = 3
+	}
+
+	db := database.GetDB()
+	var blog models.Blog
+	if err := db.First(&blog, "id = ?", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		return
+	}
+	if !canEditBlog(c, &blog) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit your own posts"})
+		return
+	}
+	from, err := loadRevision(db, &blog, fromRef)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Version not found: " + fromRef})
+		return
+	}
+	to, err := loadRevision(db, &blog, toRef)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Version not found: " + toRef})
+		return
+	}
+
+	// Markdown reads naturally line by line; HTML from the editor is often
+	// a single line, so it is compared word by word
+	granularity := c.Query("granularity")
+	if granularity == "" {
+		granularity = "word"
+		if from.ContentFormat == models.ContentFormatMarkdown && to.ContentFormat == models.ContentFormatMarkdown {
+			granularity = "line"
+		}
+	}
+	split := utils.SplitWords
+	switch granularity {
+	case "line":
+		split = utils.SplitLines
+	case "word":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "granularity must be line or word"})
+		return
+	}
+
+	edits := utils.Diff(split(from.content), split(to.content))
+	hunks := utils.DiffHunks(edits, contextSize)
+	insertions, deletions := utils.DiffStats(edits)
+
+	fields := map[string]fieldChange{}
+	if from.Title != to.Title {
+		fields["title"] = fieldChange{From: from.Title, To: to.Title}
+	}
+	if from.Language != to.Language {
+		fields["language"] = fieldChange{From: from.Language, To: to.Language}
+	}
+	if from.ContentFormat != to.ContentFormat {
+		fields["content_format"] = fieldChange{From: from.ContentFormat, To: to.ContentFormat}
+	}
+	added, removed := imageChanges(from.images, to.images)
+	reordered := len(added) == 0 && len(removed) == 0 && strings.Join(from.images, ",") != strings.Join(to.images, ",")
+
+	if c.Query("format") == "unified" {
+		var sb strings.Builder
+		for _, name := range []string{"title", "language", "content_format"} {
+			if change, ok := fields[name]; ok {
+				fmt.Fprintf(&sb, "%s: %q -> %q\n", name, change.From, change.To)
+			}
+		}
+		for _, img := range removed {
+			fmt.Fprintf(&sb, "image: -%s\n", img)
+		}
+		for _, img := range added {
+			fmt.Fprintf(&sb, "image: +%s\n", img)
+		}
+		if reordered {
+			sb.WriteString("images: reordered\n")
+		}
+		sb.WriteString(utils.UnifiedDiff(from.ID, to.ID, hunks, granularity == "line"))
+		c.String(http.StatusOK, sb.String())
+		return
+	}
+
+	if hunks == nil {
+		hunks = []utils.DiffHunk{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"from":        from,
+		"to":          to,
+		"granularity": granularity,
+		"fields":      fields,
+		"images": gin.H{
+			"added":     added,
+			"removed":   removed,
+			"reordered": reordered,
+		},
+		"content": gin.H{
+			"changed":    insertions+deletions > 0,
+			"insertions": insertions,
+			"deletions":  deletions,
+			"hunks":      hunks,
+		},
+	})
+}
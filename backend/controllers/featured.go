@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"kunals-blog-backend/database"
+	"kunals-blog-backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Pins and features lapse on their own once the expiry passes
+const (
+	pinnedActiveSQL   = "is_pinned AND (pinned_until IS NULL OR pinned_until > NOW())"
+	featuredActiveSQL = "is_featured AND (featured_until IS NULL OR featured_until > NOW())"
+)
+
+// maxHighlightOrder bounds pin and feature positions
+const maxHighlightOrder = 1000
+
+type HighlightRequest struct {
+	Order int    `json:"order"` // Lower comes first, 0 to maxHighlightOrder
+	Until string `json:"until"` // Optional expiry
+}
+
+// GetFeaturedBlogs returns the currently featured published posts in order
+func GetFeaturedBlogs(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "5"))
+	if limit <= 0 || limit > 20 {
+		limit = 5
+	}
+
+	db := database.GetDB()
+	blogs := []models.Blog{}
+	err := db.Where("is_published = ?", true).
+		Where(featuredActiveSQL).
+		Order("feature_order ASC").Order("COALESCE(published_at, created_at) DESC").
+		Preload("Author").Preload("Tags").Preload("Categories").Scopes(models.WithImages).
+		Limit(limit).
+		Find(&blogs).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch featured blogs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"blogs": blogs})
+}
+
+// ListHighlightedBlogs returns every pinned or featured blog for admins,
+// including drafts and lapsed ones
+func ListHighlightedBlogs(c *gin.Context) {
+	db := database.GetDB()
+
+	var pinned, featured []models.Blog
+	if err := db.Where("is_pinned = ?", true).Order("pin_order ASC").Order("updated_at DESC").Find(&pinned).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pinned blogs"})
+		return
+	}
+	if err := db.Where("is_featured = ?", true).Order("feature_order ASC").Order("updated_at DESC").Find(&featured).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch featured blogs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pinned": pinned, "featured": featured})
+}
+
+// setHighlight writes pin or feature columns without touching the revision,
+// since they don't change the post itself
+func setHighlight(c *gin.Context, columns func(req HighlightRequest, until *time.Time) map[string]interface{}, message string) {
+	var req HighlightRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if req.Order < 0 || req.Order > maxHighlightOrder {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "order must be between 0 and 1000"})
+		return
+	}
+	var until *time.Time
+	if req.Until != "" {
+		if until = parseDateTime(req.Until); until == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid until date"})
+			return
+		}
+		if !until.After(time.Now()) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "until must be in the future"})
+			return
+		}
+	}
+
+	db := database.GetDB()
+	var blog models.Blog
+	if err := db.First(&blog, "id = ?", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		return
+	}
+	if err := db.Model(&blog).UpdateColumns(columns(req, until)).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update blog"})
+		return
+	}
+	db.First(&blog, "id = ?", blog.ID)
+
+	c.JSON(http.StatusOK, gin.H{"message": message, "blog": blog})
+}
+
+// PinBlog pins a blog to the top of listings, optionally until a date
+func PinBlog(c *gin.Context) {
+	setHighlight(c, func(req HighlightRequest, until *time.Time) map[string]interface{} {
+		return map[string]interface{}{"is_pinned": true, "pin_order": req.Order, "pinned_until": until}
+	}, "Blog pinned")
+}
+
+// UnpinBlog removes a pin
+func UnpinBlog(c *gin.Context) {
+	setHighlight(c, func(HighlightRequest, *time.Time) map[string]interface{} {
+		return map[string]interface{}{"is_pinned": false, "pin_order": 0, "pinned_until": nil}
+	}, "Blog unpinned")
+}
+
+// FeatureBlog adds a blog to the featured list, optionally until a date
+func FeatureBlog(c *gin.Context) {
+	setHighlight(c, func(req HighlightRequest, until *time.Time) map[string]interface{} {
+		return map[string]interface{}{"is_featured": true, "feature_order": req.Order, "featured_until": until}
+	}, "Blog featured")
+}
+
+// UnfeatureBlog removes a blog from the featured list
+func UnfeatureBlog(c *gin.Context) {
+	setHighlight(c, func(HighlightRequest, *time.Time) map[string]interface{} {
+		return map[string]interface{}{"is_featured": false, "feature_order": 0, "featured_until": nil}
+	}, "Blog removed from featured")
+}
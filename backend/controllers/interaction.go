@@ -3,6 +3,7 @@ package controllers
 import (
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"kunals-blog-backend/database"
@@ -10,6 +11,7 @@ import (
 	"kunals-blog-backend/utils"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type CreateCommentRequest struct {
@@ -37,6 +39,13 @@ func CreateComment(c *gin.Context) {
 		return
 	}
 
+	// Comments are public and anonymous; keep only the small comment allowlist
+	content := strings.TrimSpace(utils.SanitizeComment(req.Content))
+	if content == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Comment is empty"})
+		return
+	}
+
 	// Get client IP for tracking
 	clientIP := c.ClientIP()
 
@@ -44,7 +53,7 @@ func CreateComment(c *gin.Context) {
 		BlogID:      req.BlogID,
 		AuthorName:  req.AuthorName,
 		Email:       req.Email,
-		Content:     req.Content,
+		Content:     content,
 		IsAnonymous: req.IsAnonymous,
 		IPAddress:   clientIP,
 	}
@@ -68,6 +77,12 @@ func GetComments(c *gin.Context) {
 	blogID := c.Param("id")
 	db := database.GetDB()
 
+	var blog models.Blog
+	if err := db.Select("id").First(&blog, "id = ?", blogID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		return
+	}
+
 	var comments []models.Comment
 	result := db.Where("blog_id = ?", blogID).Order("created_at DESC").Find(&comments)
 
@@ -79,6 +94,28 @@ func GetComments(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"comments": comments})
 }
 
+// DeleteComment removes a comment (moderation)
+func DeleteComment(c *gin.Context) {
+	blogID := c.Param("id")
+	db := database.GetDB()
+
+	result := db.Where("id = ? AND blog_id = ?", c.Param("commentId"), blogID).Delete(&models.Comment{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return
+	}
+
+	// Update comment count in blog
+	db.Model(&models.Blog{}).Where("id = ? AND comments_count > 0", blogID).
+		UpdateColumn("comments_count", gorm.Expr("comments_count - 1"))
+
+	c.JSON(http.StatusOK, gin.H{"message": "Comment deleted successfully"})
+}
+
 // LikeBlog adds a like to a blog (one per user if logged in, else per IP)
 func LikeBlog(c *gin.Context) {
 	blogID := c.Param("id")
@@ -0,0 +1,225 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"kunals-blog-backend/database"
+	"kunals-blog-backend/jobs"
+	"kunals-blog-backend/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type UpdateMediaRequest struct {
+	AltText *string `json:"alt_text"`
+	Caption *string `json:"caption"`
+}
+
+// mediaUse is a blog that shows a media item
+type mediaUse struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Slug    string `json:"slug"`
+	Trashed bool   `json:"trashed"`
+}
+
+var errUnknownMedia = errors.New("unknown media")
+
+// resolveMedia checks that every ID names a media item and drops repeats.
+// A nil list stays nil so callers can tell "unchanged" from "none". Media
+// deleted from the library counts while its file is kept, so blogs that
+// show it can still be edited.
+func resolveMedia(db *gorm.DB, ids []string) ([]string, error) {
+	if ids == nil {
+		return nil, nil
+	}
+	unique := []string{}
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if id = strings.TrimSpace(id); id != "" && !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) == 0 {
+		return unique, nil
+	}
+	var count int64
+	if err := db.Unscoped().Model(&models.Media{}).Where("id IN ?", unique).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if int(count) != len(unique) {
+		return nil, errUnknownMedia
+	}
+	return unique, nil
+}
+
+// mediaUsage lists the blogs, trashed ones included, that show the media
+// in their images or link to it from their content
+func mediaUsage(db *gorm.DB, mediaID string) ([]mediaUse, error) {
+	uses := []mediaUse{}
+	err := db.Table("blogs").
+		Select("blogs.id, blogs.title, blogs.slug, blogs.deleted_at IS NOT NULL AS trashed").
+		Where("EXISTS (SELECT 1 FROM blog_images WHERE blog_images.blog_id = blogs.id AND blog_images.media_id = ?) OR blogs.linked_media LIKE ?",
+			mediaID, `%"`+mediaID+`"%`).
+		Order("blogs.title ASC").
+		Scan(&uses).Error
+	return uses, err
+}
+
+// canEditMedia lets uploaders manage their own files and editors manage all
+func canEditMedia(c *gin.Context, media *models.Media) bool {
+	if hasPermission(c, models.PermEditOthersPosts) {
+		return true
+	}
+	return media.UploaderID != nil && *media.UploaderID == currentUserID(c)
+}
+
+// ListMedia returns the media library, newest first. q searches names,
+// alt text and captions; type filters by MIME type or its prefix.
+func ListMedia(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	db := database.GetDB()
+	query := db.Model(&models.Media{})
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		like := "%" + q + "%"
+		query = query.Where("original_name ILIKE ? OR filename ILIKE ? OR alt_text ILIKE ? OR caption ILIKE ?", like, like, like, like)
+	}
+	if mimeType := c.Query("type"); mimeType != "" {
+		query = query.Where("mime_type = ? OR mime_type LIKE ?", mimeType, strings.TrimSuffix(mimeType, "/")+"/%")
+	}
+	if uploader := c.Query("uploader"); uploader != "" {
+		query = query.Where("uploader_id = ?", uploader)
+	}
+	if c.Query("unused") == "true" {
+		query = query.Where("ref_count = 0")
+	}
+
+	var total int64
+	query.Count(&total)
+
+	media := []models.Media{}
+	if err := query.Preload("Uploader").Order("created_at DESC").Limit(limit).Offset(offset).Find(&media).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch media"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"media": media,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// GetMedia returns a media item and the blogs that use it
+func GetMedia(c *gin.Context) {
+	db := database.GetDB()
+	var media models.Media
+	if err := db.Preload("Uploader").First(&media, "id = ?", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Media not found"})
+		return
+	}
+	uses, err := mediaUsage(db, media.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch media usage"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"media": media, "used_in": uses})
+}
+
+// UpdateMedia edits a media item's alt text and caption
+func UpdateMedia(c *gin.Context) {
+	var req UpdateMediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	var media models.Media
+	if err := db.First(&media, "id = ?", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Media not found"})
+		return
+	}
+	if !canEditMedia(c, &media) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit your own uploads"})
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if req.AltText != nil {
+		alt := strings.TrimSpace(*req.AltText)
+		if utf8.RuneCountInString(alt) > 500 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Alt text must be at most 500 characters"})
+			return
+		}
+		updates["alt_text"] = alt
+	}
+	if req.Caption != nil {
+		updates["caption"] = strings.TrimSpace(*req.Caption)
+	}
+	if len(updates) > 0 {
+		if err := db.Model(&media).Updates(updates).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update media"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Media updated", "media": media})
+}
+
+// DeleteMedia removes a media item from the library. Its file is removed
+// too once no blog or version shows it or links to it; until then the blogs
+// and versions using it keep working.
+func DeleteMedia(c *gin.Context) {
+	db := database.GetDB()
+	var media models.Media
+	if err := db.First(&media, "id = ?", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Media not found"})
+		return
+	}
+	if !canEditMedia(c, &media) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only delete your own uploads"})
+		return
+	}
+
+	if err := db.Delete(&media).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete media"})
+		return
+	}
+	removed, err := jobs.CollectDeletedMedia(media.ID)
+	if err != nil {
+		// The hourly collection tries again
+		log.Printf("Failed to remove files of media %s: %v", media.ID, err)
+	}
+	if removed == 0 {
+		db.Unscoped().Select("ref_count").First(&media, "id = ?", media.ID)
+		c.JSON(http.StatusOK, gin.H{
+			"message":   "Media removed from the library; its file is kept while it is in use",
+			"ref_count": media.RefCount,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Media deleted"})
+}
@@ -0,0 +1,183 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"kunals-blog-backend/database"
+	"kunals-blog-backend/models"
+	"kunals-blog-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type MergeVersionsRequest struct {
+	Ours        string `json:"ours" binding:"required"`   // Pending version ID
+	Theirs      string `json:"theirs" binding:"required"` // Pending version ID
+	Granularity string `json:"granularity"`               // line or word; defaults as for diffs
+}
+
+// fieldConflict shows all three values of a field both sides changed
+type fieldConflict struct {
+	Base   string `json:"base"`
+	Ours   string `json:"ours"`
+	Theirs string `json:"theirs"`
+}
+
+// merge3Field merges a single value; false when both sides changed it differently
+func merge3Field(base, ours, theirs string) (string, bool) {
+	switch {
+	case ours == theirs, theirs == base:
+		return ours, true
+	case ours == base:
+		return theirs, true
+	}
+	return ours, false
+}
+
+// MergeVersions three-way merges two pending versions against the version
+// they were both based on. A clean merge becomes a new version that is
+// applied straight away when the blog is still at that base; otherwise the
+// conflicts are returned, marked up, for the editor to resolve.
+func MergeVersions(c *gin.Context) {
+	var req MergeVersionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	var blog models.Blog
+	if err := db.First(&blog, "id = ?", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		return
+	}
+	if !canEditBlog(c, &blog) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit your own posts"})
+		return
+	}
+
+	var ours, theirs models.BlogVersion
+	if err := db.First(&ours, "id = ? AND blog_id = ?", req.Ours, blog.ID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Version not found: " + req.Ours})
+		return
+	}
+	if err := db.First(&theirs, "id = ? AND blog_id = ?", req.Theirs, blog.ID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Version not found: " + req.Theirs})
+		return
+	}
+	if ours.Status != models.VersionStatusPending || theirs.Status != models.VersionStatusPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only pending versions can be merged"})
+		return
+	}
+	if ours.BaseVersionID == nil || theirs.BaseVersionID == nil || *ours.BaseVersionID != *theirs.BaseVersionID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Versions do not share a base version"})
+		return
+	}
+	var base models.BlogVersion
+	if err := db.First(&base, "id = ?", *ours.BaseVersionID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Base version no longer exists"})
+		return
+	}
+
+	merged := models.BlogVersion{
+		BlogID:        blog.ID,
+		BaseVersionID: &base.ID,
+		Message:       fmt.Sprintf("Merged %s with %s", ours.ID, theirs.ID),
+		Status:        models.VersionStatusPending,
+	}
+	if uid := currentUserID(c); uid != "" {
+		merged.AuthorID = &uid
+	}
+	fieldConflicts := map[string]fieldConflict{}
+	mergeField := func(name string, dst *string, b, o, t string) {
+		value, ok := merge3Field(b, o, t)
+		if !ok {
+			fieldConflicts[name] = fieldConflict{Base: b, Ours: o, Theirs: t}
+		}
+		*dst = value
+	}
+	mergeField("title", &merged.Title, base.Title, ours.Title, theirs.Title)
+	mergeField("language", &merged.Language, base.Language, ours.Language, theirs.Language)
+	mergeField("content_format", &merged.ContentFormat, base.ContentFormat, ours.ContentFormat, theirs.ContentFormat)
+	// Image lists merge as a whole, like the other fields
+	var images string
+	mergeField("images", &images, strings.Join(base.MediaIDs, ","), strings.Join(ours.MediaIDs, ","), strings.Join(theirs.MediaIDs, ","))
+	merged.MediaIDs = []string{}
+	if images != "" {
+		merged.MediaIDs = strings.Split(images, ",")
+	}
+	// Markdown and HTML can't be merged token by token, so the versions must
+	// agree on the format even when only one of them changed it
+	if ours.ContentFormat != theirs.ContentFormat {
+		fieldConflicts["content_format"] = fieldConflict{Base: base.ContentFormat, Ours: ours.ContentFormat, Theirs: theirs.ContentFormat}
+		c.JSON(http.StatusConflict, gin.H{"error": "Versions use different content formats", "fields": fieldConflicts})
+		return
+	}
+
+	granularity := req.Granularity
+	if granularity == "" {
+		granularity = "word"
+		if merged.ContentFormat == models.ContentFormatMarkdown {
+			granularity = "line"
+		}
+	}
+	split := utils.SplitWords
+	switch granularity {
+	case "line":
+		split = utils.SplitLines
+	case "word":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "granularity must be line or word"})
+		return
+	}
+	content, conflicts := utils.Merge3(split(base.Content), split(ours.Content), split(theirs.Content), "ours "+ours.ID, "theirs "+theirs.ID)
+	merged.Content = content
+
+	if conflicts > 0 || len(fieldConflicts) > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":     "Versions conflict",
+			"conflicts": conflicts,
+			"content":   content,
+			"fields":    fieldConflicts,
+			"merged":    merged,
+		})
+		return
+	}
+
+	merged.Content = models.SanitizeSource(merged.ContentFormat, merged.Content)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&merged).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.BlogVersion{}).
+			Where("id IN ?", []string{ours.ID, theirs.ID}).
+			UpdateColumns(map[string]interface{}{"status": models.VersionStatusMerged, "is_pending": false}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save merged version"})
+		return
+	}
+
+	// Only apply when the merge was made against what is live right now
+	if blog.CurrentVersionID == nil || *blog.CurrentVersionID != base.ID {
+		c.JSON(http.StatusOK, gin.H{"message": "Versions merged; the blog has changed since, so the merge was not applied", "applied": false, "version": merged})
+		return
+	}
+	if err := applyToLive(db, &blog, &merged, blog.Revision); err != nil {
+		if errors.Is(err, models.ErrRevisionConflict) {
+			revisionConflict(c, db, blog.ID)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to apply merged version"})
+		return
+	}
+	merged.Status = models.VersionStatusApplied
+	_ = db.Save(&merged)
+
+	setRevisionETag(c, &blog)
+	c.JSON(http.StatusOK, gin.H{"message": "Versions merged and applied", "applied": true, "version": merged, "blog": blog})
+}
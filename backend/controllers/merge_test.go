@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"kunals-blog-backend/database"
+	"kunals-blog-backend/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// testDB connects to the database in TEST_DATABASE_URL, which the tests
+// write to, and skips the test when it isn't set
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: dsn, PreferSimpleProtocol: true}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	err = db.AutoMigrate(
+		&models.User{}, &models.Blog{}, &models.Comment{}, &models.Like{}, &models.View{},
+		&models.BlogVersion{}, &models.BlogSlug{}, &models.Tag{}, &models.Category{},
+		&models.RelatedBlog{}, &models.Media{}, &models.BlogImage{},
+	)
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	database.DB = db
+	return db
+}
+
+// mergeFixture is a blog whose live version is base, with two pending
+// edits of base
+type mergeFixture struct {
+	blog               models.Blog
+	base, ours, theirs models.BlogVersion
+}
+
+func newMergeFixture(t *testing.T, db *gorm.DB, oursFormat, theirsFormat string) *mergeFixture {
+	t.Helper()
+	f := &mergeFixture{}
+	f.blog = models.Blog{Title: "Merge test", Content: "a\nb\nc\nd\ne\n", ContentFormat: models.ContentFormatMarkdown, Language: "english"}
+	if err := db.Create(&f.blog).Error; err != nil {
+		t.Fatalf("create blog: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Where("blog_id = ?", f.blog.ID).Delete(&models.BlogVersion{})
+		db.Where("blog_id = ?", f.blog.ID).Delete(&models.BlogSlug{})
+		db.Unscoped().Delete(&models.Blog{}, "id = ?", f.blog.ID)
+	})
+
+	version := func(v *models.BlogVersion, content, format, status string) {
+		*v = models.BlogVersion{BlogID: f.blog.ID, Title: f.blog.Title, Content: content, ContentFormat: format, Language: "english", MediaIDs: []string{}, Status: status}
+		if status == models.VersionStatusPending {
+			v.BaseVersionID = &f.base.ID
+		}
+		if err := db.Create(v).Error; err != nil {
+			t.Fatalf("create version: %v", err)
+		}
+	}
+	version(&f.base, f.blog.Content, models.ContentFormatMarkdown, models.VersionStatusHistory)
+	version(&f.ours, "a\nB\nc\nd\ne\n", oursFormat, models.VersionStatusPending)
+	version(&f.theirs, "a\nb\nc\nD\ne\n", theirsFormat, models.VersionStatusPending)
+	if err := db.Model(&f.blog).UpdateColumn("current_version_id", f.base.ID).Error; err != nil {
+		t.Fatalf("set current version: %v", err)
+	}
+	return f
+}
+
+// mergeTestUser is the admin merge requests are made as
+const mergeTestUser = "merge-test-admin"
+
+// mergeRequest calls MergeVersions as an admin
+func mergeRequest(t *testing.T, f *mergeFixture) (*httptest.ResponseRecorder, map[string]interface{}) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	body, _ := json.Marshal(MergeVersionsRequest{Ours: f.ours.ID, Theirs: f.theirs.ID})
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: f.blog.ID}}
+	c.Set("user_id", mergeTestUser)
+	c.Set("roles", []string{models.RoleAdmin})
+
+	MergeVersions(c)
+
+	var resp map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response %q: %v", w.Body.String(), err)
+	}
+	return w, resp
+}
+
+func versionStatus(t *testing.T, db *gorm.DB, id string) string {
+	t.Helper()
+	var v models.BlogVersion
+	if err := db.First(&v, "id = ?", id).Error; err != nil {
+		t.Fatalf("load version %s: %v", id, err)
+	}
+	return v.Status
+}
+
+func TestMergeVersionsStaleIsStoredNotApplied(t *testing.T) {
+	db := testDB(t)
+	f := newMergeFixture(t, db, models.ContentFormatMarkdown, models.ContentFormatMarkdown)
+
+	// Another edit goes live after both pending versions were started
+	moved := models.BlogVersion{BlogID: f.blog.ID, Title: f.blog.Title, Content: "other\n", ContentFormat: models.ContentFormatMarkdown, Status: models.VersionStatusHistory}
+	if err := db.Create(&moved).Error; err != nil {
+		t.Fatalf("create version: %v", err)
+	}
+	db.Model(&f.blog).UpdateColumn("current_version_id", moved.ID)
+	var before models.Blog
+	db.First(&before, "id = ?", f.blog.ID)
+
+	w, resp := mergeRequest(t, f)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	if resp["applied"] != false {
+		t.Errorf("applied = %v, want false", resp["applied"])
+	}
+
+	version, _ := resp["version"].(map[string]interface{})
+	id, _ := version["id"].(string)
+	var merged models.BlogVersion
+	if err := db.First(&merged, "id = ?", id).Error; err != nil {
+		t.Fatalf("merged version not stored: %v", err)
+	}
+	if merged.Status != models.VersionStatusPending {
+		t.Errorf("merged status = %q, want pending", merged.Status)
+	}
+	if want := "a\nB\nc\nD\ne\n"; merged.Content != want {
+		t.Errorf("merged content = %q, want %q", merged.Content, want)
+	}
+	if merged.AuthorID == nil || *merged.AuthorID != mergeTestUser {
+		t.Errorf("merged author = %v, want %s", merged.AuthorID, mergeTestUser)
+	}
+	if merged.Message == "" {
+		t.Error("merged version has no message")
+	}
+	for _, v := range []models.BlogVersion{f.ours, f.theirs} {
+		if status := versionStatus(t, db, v.ID); status != models.VersionStatusMerged {
+			t.Errorf("version %s status = %q, want merged", v.ID, status)
+		}
+	}
+
+	var after models.Blog
+	db.First(&after, "id = ?", f.blog.ID)
+	if after.Content != before.Content || after.Revision != before.Revision {
+		t.Errorf("live blog changed: revision %d -> %d", before.Revision, after.Revision)
+	}
+	if after.CurrentVersionID == nil || *after.CurrentVersionID != moved.ID {
+		t.Errorf("current version = %v, want %s", after.CurrentVersionID, moved.ID)
+	}
+}
+
+func TestMergeVersionsContentFormatConflict(t *testing.T) {
+	db := testDB(t)
+	f := newMergeFixture(t, db, models.ContentFormatMarkdown, models.ContentFormatHTML)
+
+	w, resp := mergeRequest(t, f)
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409: %s", w.Code, w.Body.String())
+	}
+	fields, _ := resp["fields"].(map[string]interface{})
+	if _, ok := fields["content_format"]; !ok {
+		t.Errorf("fields = %v, want a content_format conflict", resp["fields"])
+	}
+
+	var count int64
+	db.Model(&models.BlogVersion{}).Where("blog_id = ?", f.blog.ID).Count(&count)
+	if count != 3 {
+		t.Errorf("blog has %d versions, want the 3 it started with", count)
+	}
+	for _, v := range []models.BlogVersion{f.ours, f.theirs} {
+		if status := versionStatus(t, db, v.ID); status != models.VersionStatusPending {
+			t.Errorf("version %s status = %q, want pending", v.ID, status)
+		}
+	}
+}
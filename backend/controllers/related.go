@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"kunals-blog-backend/database"
+	"kunals-blog-backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetRelatedBlogs returns published posts similar to the given one. The
+// list is precomputed in the background so this is a single indexed read.
+func GetRelatedBlogs(c *gin.Context) {
+	blogID := c.Param("id")
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "3"))
+	if limit <= 0 || limit > 6 {
+		limit = 3
+	}
+
+	db := database.GetDB()
+	blogs := []models.Blog{}
+	err := db.Model(&models.Blog{}).
+		Select("blogs.id, blogs.title, blogs.slug, blogs.preview, blogs.language, blogs.published_at, blogs.reading_time, blogs.is_published").
+		Joins("JOIN related_blogs ON related_blogs.related_id = blogs.id").
+		Where("related_blogs.blog_id = ? AND blogs.is_published = ?", blogID, true).
+		Order("related_blogs.rank ASC").
+		Limit(limit).
+		Scopes(models.WithImages).
+		Find(&blogs).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch related blogs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"blogs": blogs})
+}
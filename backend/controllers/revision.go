@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"kunals-blog-backend/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type ApplyVersionRequest struct {
+	BaseRevision *int `json:"base_revision"` // Alternative to the If-Match header
+}
+
+// setRevisionETag sends the blog's revision as its ETag
+func setRevisionETag(c *gin.Context, blog *models.Blog) {
+	c.Header("ETag", fmt.Sprintf("%q", strconv.Itoa(blog.Revision)))
+}
+
+// baseRevision returns the revision the client's edit is based on, taken
+// from base_revision or else the If-Match header. Without either it writes
+// 428 and returns false.
+func baseRevision(c *gin.Context, field *int) (int, bool) {
+	if field != nil {
+		return *field, true
+	}
+	if match := strings.TrimSpace(c.GetHeader("If-Match")); match != "" {
+		tag := strings.Trim(strings.TrimPrefix(match, "W/"), `"`)
+		if rev, err := strconv.Atoi(tag); err == nil {
+			return rev, true
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "If-Match must be a blog revision ETag"})
+		return 0, false
+	}
+	c.JSON(http.StatusPreconditionRequired, gin.H{"error": "If-Match header or base_revision is required"})
+	return 0, false
+}
+
+// applyBaseRevision reads the base revision for apply and restore, whose
+// JSON body is optional
+func applyBaseRevision(c *gin.Context) (int, bool) {
+	var req ApplyVersionRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return 0, false
+		}
+	}
+	return baseRevision(c, req.BaseRevision)
+}
+
+// revisionConflict answers 409 with the blog as it is now so the editor
+// can show both sides instead of losing work
+func revisionConflict(c *gin.Context, db *gorm.DB, blogID string) {
+	var current models.Blog
+	db.Preload("Tags").Preload("Categories").First(&current, "id = ?", blogID)
+	setRevisionETag(c, &current)
+	c.JSON(http.StatusConflict, gin.H{
+		"error":    "Blog was changed by someone else",
+		"revision": current.Revision,
+		"blog":     current,
+	})
+}
@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"kunals-blog-backend/database"
+	"kunals-blog-backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ScheduleRequest struct {
+	PublishAt string `json:"publish_at" binding:"required"`
+}
+
+// ListScheduledBlogs returns blogs waiting to be published, soonest first
+func ListScheduledBlogs(c *gin.Context) {
+	db := database.GetDB()
+
+	var blogs []models.Blog
+	err := db.Where("is_published = ? AND scheduled_at IS NOT NULL", false).
+		Order("scheduled_at ASC").
+		Find(&blogs).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch scheduled blogs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"blogs": blogs})
+}
+
+// ScheduleBlog schedules a draft, or moves an existing schedule, to a future time
+func ScheduleBlog(c *gin.Context) {
+	var req ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	publishAt := parseDateTime(req.PublishAt)
+	if publishAt == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid publish_at date"})
+		return
+	}
+	if !publishAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "publish_at must be in the future"})
+		return
+	}
+
+	db := database.GetDB()
+	var blog models.Blog
+	if err := db.First(&blog, "id = ?", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		return
+	}
+	if blog.IsPublished {
+		c.JSON(http.StatusConflict, gin.H{"error": "Blog is already published"})
+		return
+	}
+
+	blog.ScheduledAt = publishAt
+	if err := savePublishState(db, &blog); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to schedule blog"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Blog scheduled for publishing", "blog": blog})
+}
+
+// CancelSchedule turns a scheduled blog back into a draft
+func CancelSchedule(c *gin.Context) {
+	db := database.GetDB()
+	var blog models.Blog
+	if err := db.First(&blog, "id = ?", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		return
+	}
+	if blog.IsPublished || blog.ScheduledAt == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Blog is not scheduled"})
+		return
+	}
+
+	blog.ScheduledAt = nil
+	if err := savePublishState(db, &blog); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel schedule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Schedule cancelled", "blog": blog})
+}
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"kunals-blog-backend/database"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type searchResult struct {
+	ID             string     `json:"id"`
+	Title          string     `json:"title"`
+	Slug           string     `json:"slug"`
+	Preview        string     `json:"preview"`
+	Language       string     `json:"language"`
+	PublishedAt    *time.Time `json:"published_at"`
+	Rank           float64    `json:"rank"`
+	TitleHighlight string     `json:"title_highlight"`
+	Snippet        string     `json:"snippet"`
+}
+
+// Each row is ranked and highlighted with its own search_config, so the
+// query is stemmed the same way as the post it is compared against.
+// Matching uses searchMatch instead, which the index can serve.
+const (
+	searchQueryExpr = "websearch_to_tsquery(search_config, ?)"
+	searchSelect    = `id, title, slug, preview, language, published_at,
+		ts_rank_cd(search_vector, ` + searchQueryExpr + `, 32) AS rank,
+		ts_headline(search_config, title, ` + searchQueryExpr + `,
+			'HighlightAll=true, StartSel=<mark>, StopSel=</mark>') AS title_highlight,
+		ts_headline(search_config, regexp_replace(coalesce(content_html, content, ''), '<[^>]*>', ' ', 'g'), ` + searchQueryExpr + `,
+			'StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=" … "') AS snippet`
+)
+
+// refreshSearchIndex keeps the search document in sync after a write.
+// Failures are logged only; the write itself already succeeded.
+func refreshSearchIndex(db *gorm.DB, blogID string) {
+	if err := database.RefreshSearchVector(db, blogID); err != nil {
+		log.Printf("Failed to refresh search index for blog %s: %v", blogID, err)
+	}
+}
+
+// searchMatch builds the condition matching posts against q. The tsquery
+// has to be a constant for the GIN index on search_vector to apply, so
+// there is one per text search configuration, each limited to the posts
+// stored with it.
+func searchMatch(configs []string, q string) (string, []interface{}) {
+	clauses := make([]string, 0, len(configs))
+	args := make([]interface{}, 0, 3*len(configs))
+	for _, cfg := range configs {
+		clauses = append(clauses, "(search_config = ?::regconfig AND search_vector @@ websearch_to_tsquery(?::regconfig, ?))")
+		args = append(args, cfg, cfg, q)
+	}
+	return strings.Join(clauses, " OR "), args
+}
+
+// SearchBlogs runs a full-text search over published posts
+func SearchBlogs(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query required"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 || limit > 50 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	db := database.GetDB()
+	query := db.Table("blogs").Where("is_published = ? AND deleted_at IS NULL", true)
+	var configs []string
+	if language := c.Query("language"); language != "" {
+		query = query.Where("language = ?", language)
+		configs = []string{database.SearchConfig(language)}
+	} else if err := db.Raw("SELECT DISTINCT search_config::text FROM blogs WHERE is_published AND deleted_at IS NULL").Scan(&configs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search blogs"})
+		return
+	}
+	if len(configs) == 0 {
+		configs = []string{database.SearchConfig("")}
+	}
+	match, args := searchMatch(configs, q)
+	query = query.Where(match, args...)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search blogs"})
+		return
+	}
+
+	results := []searchResult{}
+	err := query.Select(searchSelect, q, q, q).
+		Order("rank DESC").Order("published_at DESC").
+		Limit(limit).Offset(offset).
+		Scan(&results).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search blogs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"query":   q,
+		"results": results,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"net/http"
+
+	"kunals-blog-backend/database"
+	"kunals-blog-backend/models"
+	"kunals-blog-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// setBlogSlug moves a blog to a new slug (made unique) and keeps the old one
+// in the slug history so existing links keep resolving. The caller saves blog.
+func setBlogSlug(db *gorm.DB, blog *models.Blog, value string) error {
+	slug := models.UniqueBlogSlug(db, value, blog.ID)
+	if slug == blog.Slug {
+		return nil
+	}
+	// The new slug may be one this blog used before; it is live again now
+	if err := db.Where("blog_id = ? AND slug = ?", blog.ID, slug).Delete(&models.BlogSlug{}).Error; err != nil {
+		return err
+	}
+	if blog.Slug != "" {
+		if err := db.Create(&models.BlogSlug{BlogID: blog.ID, Slug: blog.Slug}).Error; err != nil {
+			return err
+		}
+	}
+	blog.Slug = slug
+	return nil
+}
+
+// GetBlogBySlug returns a published blog by its slug. Old slugs answer with
+// a redirect to the current one. Drafts and scheduled posts are not found,
+// so their slugs don't leak.
+func GetBlogBySlug(c *gin.Context) {
+	slug := utils.Slugify(c.Param("slug"))
+	db := database.GetDB()
+
+	var blog models.Blog
+	if err := db.Select("id").First(&blog, "slug = ? AND is_published = ?", slug, true).Error; err == nil {
+		showBlog(c, blog.ID)
+		return
+	}
+
+	var old models.BlogSlug
+	if err := db.First(&old, "slug = ?", slug).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		return
+	}
+	if err := db.Select("id, slug").First(&blog, "id = ? AND is_published = ?", old.BlogID, true).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		return
+	}
+
+	c.Header("Location", "/api/public/blogs/slug/"+blog.Slug)
+	c.JSON(http.StatusMovedPermanently, gin.H{
+		"message":  "Blog has moved",
+		"redirect": true,
+		"id":       blog.ID,
+		"slug":     blog.Slug,
+	})
+}
@@ -0,0 +1,321 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"kunals-blog-backend/database"
+	"kunals-blog-backend/models"
+	"kunals-blog-backend/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type TagRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type CategoryRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+// taxonomyCount is a tag or category together with how many posts use it
+type taxonomyCount struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Description string `json:"description,omitempty"`
+	PostCount   int64  `json:"post_count"`
+}
+
+var errUnknownCategory = errors.New("unknown category")
+
+// resolveTags finds tags by name, creating the ones that don't exist yet
+func resolveTags(db *gorm.DB, names []string) ([]models.Tag, error) {
+	tags := make([]models.Tag, 0, len(names))
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		slug := utils.Slugify(name)
+		if seen[slug] {
+			continue
+		}
+		seen[slug] = true
+
+		var tag models.Tag
+		if err := db.Where(models.Tag{Slug: slug}).Attrs(models.Tag{Name: name}).FirstOrCreate(&tag).Error; err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// resolveCategories looks up existing categories by slug
+func resolveCategories(db *gorm.DB, slugs []string) ([]models.Category, error) {
+	categories := make([]models.Category, 0, len(slugs))
+	for _, slug := range slugs {
+		if strings.TrimSpace(slug) == "" {
+			continue
+		}
+		var category models.Category
+		if err := db.First(&category, "slug = ?", utils.Slugify(slug)).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errUnknownCategory
+			}
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+	return categories, nil
+}
+
+// queryList reads a list query parameter given either repeated or comma separated
+func queryList(c *gin.Context, key string) []string {
+	var values []string
+	for _, raw := range c.QueryArray(key) {
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+// ListTags returns all tags with the number of posts using each
+func ListTags(c *gin.Context) {
+	db := database.GetDB()
+	publishedOnly := c.DefaultQuery("published_only", "true") == "true"
+
+	join := "LEFT JOIN blogs ON blogs.id = blog_tags.blog_id AND blogs.deleted_at IS NULL"
+	if publishedOnly {
+		join += " AND blogs.is_published = true"
+	}
+
+	var tags []taxonomyCount
+	err := db.Table("tags").
+		Select("tags.id, tags.name, tags.slug, COUNT(blogs.id) AS post_count").
+		Joins("LEFT JOIN blog_tags ON blog_tags.tag_id = tags.id").
+		Joins(join).
+		Group("tags.id").
+		Order("post_count DESC").Order("tags.name ASC").
+		Scan(&tags).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
+// CreateTag creates a new tag
+func CreateTag(c *gin.Context) {
+	var req TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	tag := models.Tag{Name: strings.TrimSpace(req.Name)}
+
+	var existing int64
+	db.Model(&models.Tag{}).Where("name = ? OR slug = ?", tag.Name, utils.Slugify(tag.Name)).Count(&existing)
+	if existing > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Tag already exists"})
+		return
+	}
+
+	if err := db.Create(&tag).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create tag"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Tag created successfully", "tag": tag})
+}
+
+// UpdateTag renames a tag
+func UpdateTag(c *gin.Context) {
+	var req TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	var tag models.Tag
+	if err := db.First(&tag, "id = ?", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return
+	}
+
+	tag.Name = strings.TrimSpace(req.Name)
+	tag.Slug = utils.Slugify(tag.Name)
+
+	var existing int64
+	db.Model(&models.Tag{}).Where("(name = ? OR slug = ?) AND id <> ?", tag.Name, tag.Slug, tag.ID).Count(&existing)
+	if existing > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Tag already exists"})
+		return
+	}
+
+	if err := db.Save(&tag).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update tag"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tag updated successfully", "tag": tag})
+}
+
+// DeleteTag deletes a tag and removes it from all posts
+func DeleteTag(c *gin.Context) {
+	tagID := c.Param("id")
+	db := database.GetDB()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM blog_tags WHERE tag_id = ?", tagID).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.Tag{}, "id = ?", tagID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete tag"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Tag deleted successfully"})
+}
+
+// ListCategories returns all categories with the number of posts in each
+func ListCategories(c *gin.Context) {
+	db := database.GetDB()
+	publishedOnly := c.DefaultQuery("published_only", "true") == "true"
+
+	join := "LEFT JOIN blogs ON blogs.id = blog_categories.blog_id AND blogs.deleted_at IS NULL"
+	if publishedOnly {
+		join += " AND blogs.is_published = true"
+	}
+
+	var categories []taxonomyCount
+	err := db.Table("categories").
+		Select("categories.id, categories.name, categories.slug, categories.description, COUNT(blogs.id) AS post_count").
+		Joins("LEFT JOIN blog_categories ON blog_categories.category_id = categories.id").
+		Joins(join).
+		Group("categories.id").
+		Order("categories.name ASC").
+		Scan(&categories).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch categories"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"categories": categories})
+}
+
+// CreateCategory creates a new category
+func CreateCategory(c *gin.Context) {
+	var req CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	category := models.Category{Name: strings.TrimSpace(req.Name), Description: req.Description}
+
+	var existing int64
+	db.Model(&models.Category{}).Where("name = ? OR slug = ?", category.Name, utils.Slugify(category.Name)).Count(&existing)
+	if existing > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Category already exists"})
+		return
+	}
+
+	if err := db.Create(&category).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create category"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Category created successfully", "category": category})
+}
+
+// UpdateCategory renames a category or changes its description
+func UpdateCategory(c *gin.Context) {
+	var req CategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := database.GetDB()
+	var category models.Category
+	if err := db.First(&category, "id = ?", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
+
+	category.Name = strings.TrimSpace(req.Name)
+	category.Slug = utils.Slugify(category.Name)
+	category.Description = req.Description
+
+	var existing int64
+	db.Model(&models.Category{}).Where("(name = ? OR slug = ?) AND id <> ?", category.Name, category.Slug, category.ID).Count(&existing)
+	if existing > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Category already exists"})
+		return
+	}
+
+	if err := db.Save(&category).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update category"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Category updated successfully", "category": category})
+}
+
+// DeleteCategory deletes a category and removes it from all posts
+func DeleteCategory(c *gin.Context) {
+	categoryID := c.Param("id")
+	db := database.GetDB()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM blog_categories WHERE category_id = ?", categoryID).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.Category{}, "id = ?", categoryID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete category"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Category deleted successfully"})
+}
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+
+	"kunals-blog-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	errTranslationSource = errors.New("translation source not found")
+	errTranslationExists = errors.New("translation already exists")
+)
+
+// translationSummary is what a post shows about its sibling translations
+type translationSummary struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Slug     string `json:"slug"`
+	Language string `json:"language"`
+}
+
+// joinTranslationGroup puts blog in the same translation group as sourceID.
+// The source becomes the group's root if it isn't in a group yet.
+func joinTranslationGroup(db *gorm.DB, blog *models.Blog, sourceID string) error {
+	var source models.Blog
+	if err := db.Select("id, translation_group_id").First(&source, "id = ?", sourceID).Error; err != nil {
+		return errTranslationSource
+	}
+
+	groupID := source.ID
+	if source.TranslationGroupID != nil {
+		groupID = *source.TranslationGroupID
+	}
+
+	var sameLanguage int64
+	db.Model(&models.Blog{}).
+		Where("COALESCE(translation_group_id, id) = ? AND language = ? AND id <> ?", groupID, blog.Language, blog.ID).
+		Count(&sameLanguage)
+	if sameLanguage > 0 {
+		return errTranslationExists
+	}
+
+	if source.TranslationGroupID == nil {
+		if err := db.Model(&models.Blog{}).Where("id = ?", source.ID).UpdateColumn("translation_group_id", groupID).Error; err != nil {
+			return err
+		}
+	}
+	blog.TranslationGroupID = &groupID
+	return nil
+}
+
+// blogTranslations lists the published translations of blog, excluding itself
+func blogTranslations(db *gorm.DB, blog *models.Blog) []translationSummary {
+	translations := []translationSummary{}
+	if blog.TranslationGroupID == nil {
+		return translations
+	}
+	db.Model(&models.Blog{}).
+		Select("id, title, slug, language").
+		Where("translation_group_id = ? AND id <> ? AND is_published = ?", *blog.TranslationGroupID, blog.ID, true).
+		Order("language ASC").
+		Scan(&translations)
+	return translations
+}
+
+// pickTranslations selects one post per translation group from the posts
+// query matches, preferring the languages in order. Posts outside a group
+// count as a group of their own.
+func pickTranslations(query *gorm.DB, preferred []string) *gorm.DB {
+	rank := "0"
+	vars := []interface{}{}
+	if len(preferred) > 0 {
+		rank = "CASE language"
+		for i, lang := range preferred {
+			rank += " WHEN ? THEN " + strconv.Itoa(i)
+			vars = append(vars, lang)
+		}
+		rank += " ELSE " + strconv.Itoa(len(preferred)) + " END"
+	}
+
+	return query.Select("DISTINCT ON (COALESCE(translation_group_id, id)) id").Order(clause.OrderBy{Expression: clause.Expr{
+		SQL:                "COALESCE(translation_group_id, id), " + rank + ", COALESCE(published_at, created_at) DESC",
+		Vars:               vars,
+		WithoutParentheses: true,
+	}})
+}
@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"kunals-blog-backend/config"
+	"kunals-blog-backend/database"
+	"kunals-blog-backend/jobs"
+	"kunals-blog-backend/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ListTrash returns deleted blogs, most recently deleted first
+func ListTrash(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 || limit > 50 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	db := database.GetDB()
+	query := db.Unscoped().Model(&models.Blog{}).Where("deleted_at IS NOT NULL")
+
+	var total int64
+	query.Count(&total)
+
+	var blogs []models.Blog
+	if err := query.Order("deleted_at DESC").Limit(limit).Offset(offset).Find(&blogs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trash"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"blogs":          blogs,
+		"retention_days": config.GetConfig().TrashRetentionDays,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// RestoreBlog takes a blog out of the trash. Comments, likes, views and
+// versions were never removed, so they come back with it.
+func RestoreBlog(c *gin.Context) {
+	blogID := c.Param("id")
+	db := database.GetDB()
+
+	result := db.Unscoped().Model(&models.Blog{}).
+		Where("id = ? AND deleted_at IS NOT NULL", blogID).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore blog"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found in trash"})
+		return
+	}
+
+	jobs.RequestRelatedRefresh()
+
+	var blog models.Blog
+	db.First(&blog, "id = ?", blogID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Blog restored successfully", "blog": blog})
+}
+
+// PurgeBlog permanently deletes a trashed blog and everything attached to it
+func PurgeBlog(c *gin.Context) {
+	blogID := c.Param("id")
+	db := database.GetDB()
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var blog models.Blog
+		if err := tx.Unscoped().Select("id").First(&blog, "id = ? AND deleted_at IS NOT NULL", blogID).Error; err != nil {
+			return err
+		}
+		return models.PurgeBlog(tx, blogID)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found in trash"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to purge blog"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Blog purged permanently"})
+}
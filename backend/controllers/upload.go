@@ -1,20 +1,70 @@
 package controllers
 
 import (
-	"fmt"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"image"
 	"io"
+	"log"
+	"mime"
 	"net/http"
-	"os"
 	"path/filepath"
-	"strings"
-	"time"
+	"strconv"
 
-	"kunals-blog-backend/config"
+	"kunals-blog-backend/database"
+	"kunals-blog-backend/models"
+	"kunals-blog-backend/storage"
+	"kunals-blog-backend/utils"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+const (
+	maxUploadSize  = 5 * 1024 * 1024
+	maxImagePixels = 40 * 1000 * 1000
+)
+
+// uploadTypes maps the image types accepted for upload to the extension
+// they are stored with
+var uploadTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// mediaByHash finds the media holding a file, deleted from the library or not
+func mediaByHash(db *gorm.DB, hash string) (*models.Media, error) {
+	var media models.Media
+	if err := db.Unscoped().Where("sha256 = ?", hash).First(&media).Error; err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// uploadResponse is the reply to an upload, new or already in the library
+func uploadResponse(media *models.Media, duplicate bool) gin.H {
+	message := "Image uploaded successfully"
+	if duplicate {
+		message = "Image already in the media library"
+	}
+	return gin.H{
+		"message":   message,
+		"filename":  media.Filename,
+		"url":       media.URL,
+		"srcset":    media.SrcSet,
+		"variants":  media.Variants,
+		"media":     media,
+		"duplicate": duplicate,
+	}
+}
+
+// UploadImage stores an image in the media library. The file is checked
+// by content, decoded in full and written out again without metadata.
+// Files are named by the SHA-256 of what is stored, so uploading the same
+// image again returns the media it is already in.
 func UploadImage(c *gin.Context) {
 	// Get the file from the request
 	file, header, err := c.Request.FormFile("image")
@@ -24,75 +74,174 @@ func UploadImage(c *gin.Context) {
 	}
 	defer file.Close()
 
-	// Validate file type
-	allowedTypes := map[string]bool{
-		".jpg":  true,
-		".jpeg": true,
-		".png":  true,
-		".gif":  true,
-		".webp": true,
+	// Read at most one byte past the limit, so the size checked is what was
+	// actually sent rather than what the multipart header claims
+	data, err := io.ReadAll(io.LimitReader(file, maxUploadSize+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read image"})
+		return
+	}
+	if len(data) > maxUploadSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File size too large. Maximum 5MB allowed"})
+		return
 	}
 
-	ext := strings.ToLower(filepath.Ext(header.Filename))
-	if !allowedTypes[ext] {
+	// The format comes from the file's magic bytes, not its name
+	mimeType := http.DetectContentType(data)
+	ext, ok := uploadTypes[mimeType]
+	if !ok {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file type. Only JPG, PNG, GIF, and WebP are allowed"})
 		return
 	}
-
-	// Validate file size (max 5MB)
-	if header.Size > 5*1024*1024 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "File size too large. Maximum 5MB allowed"})
+	// Check the dimensions before decoding so a small file can't claim a
+	// huge image
+	imgCfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is not a valid image"})
 		return
 	}
-
-	cfg := config.GetConfig()
-
-	// Create upload directory if it doesn't exist
-	uploadDir := cfg.UploadPath
-	if err := os.MkdirAll(uploadDir, 0755); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create upload directory"})
+	if imgCfg.Width*imgCfg.Height > maxImagePixels {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Image dimensions too large"})
 		return
 	}
-
-	// Generate unique filename
-	filename := fmt.Sprintf("%d_%s%s", time.Now().Unix(), uuid.New().String()[:8], ext)
-	filepath := filepath.Join(uploadDir, filename)
-
-	// Create the destination file
-	dst, err := os.Create(filepath)
+	// Every frame of an animation is decoded in full, so they count together
+	if mimeType == "image/gif" {
+		pixels, err := utils.GIFPixels(data)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "File is not a valid image"})
+			return
+		}
+		if pixels > maxImagePixels {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Animation too large"})
+			return
+		}
+	}
+	// Decoding in full proves the image is intact; encoding it again drops
+	// EXIF data such as GPS position
+	clean, err := utils.CleanImage(data, mimeType)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create file"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is not a valid image"})
 		return
 	}
-	defer dst.Close()
 
-	// Copy the uploaded file to destination
-	if _, err := io.Copy(dst, file); err != nil {
+	db := database.GetDB()
+	sum := sha256.Sum256(clean.Data)
+	hash := hex.EncodeToString(sum[:])
+	if existing, err := mediaByHash(db, hash); err == nil {
+		// Uploading a file deleted from the library brings it back
+		if existing.DeletedAt.Valid {
+			result := db.Unscoped().Model(existing).Update("deleted_at", nil)
+			if result.Error != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save media"})
+				return
+			}
+			existing.DeletedAt = gorm.DeletedAt{}
+			// Collected in the meantime; store the file again below
+			if result.RowsAffected == 0 {
+				existing = nil
+			}
+		}
+		if existing != nil {
+			c.JSON(http.StatusOK, uploadResponse(existing, true))
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	store := storage.Default()
+
+	filename := hash + ext
+	if err := store.Put(ctx, filename, bytes.NewReader(clean.Data), int64(len(clean.Data)), mimeType); err != nil {
+		log.Printf("Failed to store %s: %v", filename, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
 		return
 	}
 
-	// Return the file URL
-	fileURL := fmt.Sprintf("/uploads/%s", filename)
+	// Record it in the media library
+	media := models.Media{
+		Filename:     filename,
+		OriginalName: header.Filename,
+		Size:         int64(len(clean.Data)),
+		MimeType:     mimeType,
+		SHA256:       hash,
+		Width:        clean.Width,
+		Height:       clean.Height,
+	}
+	// Resized copies let pages pick a size with srcset
+	if media.Variants, err = makeVariants(ctx, store, filename, clean.Image); err != nil {
+		log.Printf("Failed to resize %s: %v", filename, err)
+	}
+	if uid := currentUserID(c); uid != "" {
+		media.UploaderID = &uid
+	}
+	if err := db.Create(&media).Error; err != nil {
+		// The same file uploaded at the same moment; the files written are
+		// the other upload's as well, so they stay
+		if existing, findErr := mediaByHash(db, hash); findErr == nil {
+			c.JSON(http.StatusOK, uploadResponse(existing, true))
+			return
+		}
+		store.Delete(ctx, filename)
+		removeVariants(ctx, store, filename)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save media"})
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message":  "Image uploaded successfully",
-		"filename": filename,
-		"url":      fileURL,
-	})
+	c.JSON(http.StatusOK, uploadResponse(&media, false))
 }
 
+// ServeImage sends an uploaded image, or redirects to it when the storage
+// backend can hand out its own URLs. ?w= asks for a width; the smallest
+// cached copy at least that wide is sent, made on first request.
 func ServeImage(c *gin.Context) {
 	filename := c.Param("filename")
-	cfg := config.GetConfig()
-
-	filepath := filepath.Join(cfg.UploadPath, filename)
+	ctx := c.Request.Context()
+	store := storage.Default()
 
 	// Check if file exists
-	if _, err := os.Stat(filepath); os.IsNotExist(err) {
+	if ok, err := store.Exists(ctx, filename); err != nil || !ok {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Image not found"})
 		return
 	}
 
-	c.File(filepath)
+	if w := c.Query("w"); w != "" {
+		width, err := strconv.Atoi(w)
+		if err != nil || width <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "w must be a positive width"})
+			return
+		}
+		variant, err := widthVariant(ctx, store, filename, width)
+		if err != nil {
+			log.Printf("Failed to resize %s to %d: %v", filename, width, err)
+		}
+		if variant != "" {
+			filename = variant
+		}
+	}
+
+	url, err := store.URL(ctx, filename)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to locate image"})
+		return
+	}
+	if url != "" {
+		c.Redirect(http.StatusFound, url)
+		return
+	}
+	if local, ok := store.(*storage.Local); ok {
+		path, err := local.Path(filename)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Image not found"})
+			return
+		}
+		c.File(path)
+		return
+	}
+	r, err := store.Get(ctx, filename)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Image not found"})
+		return
+	}
+	defer r.Close()
+	c.DataFromReader(http.StatusOK, -1, mime.TypeByExtension(filepath.Ext(filename)), r, nil)
 }
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"kunals-blog-backend/database"
+	"kunals-blog-backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SetRolesRequest struct {
+	Roles []string `json:"roles" binding:"required"`
+}
+
+// currentUserID returns the ID AuthMiddleware put on the context
+func currentUserID(c *gin.Context) string {
+	userID, _ := c.Get("user_id")
+	uid, _ := userID.(string)
+	return uid
+}
+
+// hasPermission checks the roles AuthMiddleware put on the context
+func hasPermission(c *gin.Context, perm string) bool {
+	rolesVal, _ := c.Get("roles")
+	roles, _ := rolesVal.([]string)
+	return models.HasPermission(roles, perm)
+}
+
+// canEditBlog allows authors to edit their own posts and anyone with
+// edit_others_posts to edit the rest
+func canEditBlog(c *gin.Context, blog *models.Blog) bool {
+	if hasPermission(c, models.PermEditOthersPosts) {
+		return true
+	}
+	return blog.AuthorID != nil && *blog.AuthorID == currentUserID(c)
+}
+
+// ListRoles returns every role with the permissions it grants
+func ListRoles(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"roles": models.RolePermissions})
+}
+
+// ListUsers returns user accounts with their roles
+func ListUsers(c *gin.Context) {
+	db := database.GetDB()
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	query := db.Model(&models.User{})
+	if q := c.Query("q"); q != "" {
+		query = query.Where("username ILIKE ?", "%"+q+"%")
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var users []models.User
+	if err := query.Order("created_at ASC").Limit(limit).Offset(offset).Find(&users).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users"})
+		return
+	}
+	for i := range users {
+		users[i].Roles = users[i].EffectiveRoles()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users": users,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// SetUserRoles replaces a user's roles. New roles apply from the user's
+// next request.
+func SetUserRoles(c *gin.Context) {
+	var req SetRolesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	roles := []string{}
+	seen := map[string]bool{}
+	for _, role := range req.Roles {
+		if !models.IsValidRole(role) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown role: " + role})
+			return
+		}
+		if !seen[role] {
+			seen[role] = true
+			roles = append(roles, role)
+		}
+	}
+	if len(roles) == 0 {
+		roles = []string{models.RoleReader}
+	}
+
+	db := database.GetDB()
+	var user models.User
+	if err := db.First(&user, "id = ?", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	// Never leave the site without an admin
+	if user.IsAdmin && !seen[models.RoleAdmin] {
+		var admins int64
+		db.Model(&models.User{}).Where("is_admin = ? AND id <> ?", true, user.ID).Count(&admins)
+		if admins == 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Cannot remove the last admin"})
+			return
+		}
+	}
+
+	user.Roles = roles
+	user.IsAdmin = seen[models.RoleAdmin]
+	if err := db.Save(&user).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update roles"})
+		return
+	}
+
+	user.Password = ""
+	c.JSON(http.StatusOK, gin.H{"message": "Roles updated successfully", "user": user})
+}
@@ -0,0 +1,159 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"path/filepath"
+	"strings"
+
+	"kunals-blog-backend/database"
+	"kunals-blog-backend/models"
+	"kunals-blog-backend/storage"
+	"kunals-blog-backend/utils"
+)
+
+// imageVariants are the sizes made for every uploaded image wider than them
+var imageVariants = []struct {
+	Name  string
+	Width int
+}{
+	{"thumb", 320},
+	{"medium", 800},
+	{"large", 1600},
+}
+
+// variantStep rounds widths asked for through ServeImage up, so arbitrary
+// widths can't fill the storage with copies. The standard widths are
+// multiples of it so they are reused.
+const variantStep = 160
+
+// variantFormat is the format resized copies of a file are written in.
+// There is no WebP encoder, so WebP images are resized to JPEG. GIFs are
+// not resized so animations survive.
+func variantFormat(filename string) (format, ext string, ok bool) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".jpg", ".jpeg", ".webp":
+		return "jpeg", ".jpg", true
+	case ".png":
+		return "png", ".png", true
+	}
+	return "", "", false
+}
+
+// variantFilename names the copy of filename resized to width
+func variantFilename(filename string, width int) string {
+	_, ext, _ := variantFormat(filename)
+	return fmt.Sprintf("%s%d%s", models.VariantPrefix(filename), width, ext)
+}
+
+// writeVariant resizes img to width and stores it next to the original
+func writeVariant(ctx context.Context, store storage.Storage, filename string, img image.Image, width int) (models.ImageVariant, error) {
+	format, _, _ := variantFormat(filename)
+	resized := utils.ResizeWidth(img, width)
+	name := variantFilename(filename, width)
+
+	var buf bytes.Buffer
+	if err := utils.EncodeImage(&buf, resized, format); err != nil {
+		return models.ImageVariant{}, err
+	}
+	if err := store.Put(ctx, name, &buf, int64(buf.Len()), "image/"+format); err != nil {
+		return models.ImageVariant{}, err
+	}
+	return models.ImageVariant{Width: width, Height: resized.Bounds().Dy(), Filename: name}, nil
+}
+
+// decodeUpload decodes a stored image
+func decodeUpload(ctx context.Context, store storage.Storage, filename string) (image.Image, error) {
+	r, err := store.Get(ctx, filename)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	img, _, err := image.Decode(r)
+	return img, err
+}
+
+// makeVariants writes the standard resized copies of an uploaded image
+func makeVariants(ctx context.Context, store storage.Storage, filename string, img image.Image) ([]models.ImageVariant, error) {
+	if _, _, ok := variantFormat(filename); !ok {
+		return nil, nil
+	}
+	var variants []models.ImageVariant
+	for _, size := range imageVariants {
+		if size.Width >= img.Bounds().Dx() {
+			break
+		}
+		variant, err := writeVariant(ctx, store, filename, img, size.Width)
+		if err != nil {
+			return variants, err
+		}
+		variant.Name = size.Name
+		variants = append(variants, variant)
+	}
+	return variants, nil
+}
+
+// originalWidth is the width of a stored image, from the media library or
+// else from the file's header
+func originalWidth(ctx context.Context, store storage.Storage, filename string) (int, error) {
+	var media models.Media
+	if err := database.GetDB().Unscoped().Select("width").Where("filename = ?", filename).First(&media).Error; err == nil && media.Width > 0 {
+		return media.Width, nil
+	}
+	r, err := store.Get(ctx, filename)
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+	cfg, _, err := image.DecodeConfig(r)
+	return cfg.Width, err
+}
+
+// widthVariant returns the name of filename resized to at least width,
+// making and caching it on first use. It returns "" when the original is
+// the best fit, which is known before anything is decoded.
+func widthVariant(ctx context.Context, store storage.Storage, filename string, width int) (string, error) {
+	if _, _, ok := variantFormat(filename); !ok {
+		return "", nil
+	}
+	width = (width + variantStep - 1) / variantStep * variantStep
+	original, err := originalWidth(ctx, store, filename)
+	if err != nil {
+		return "", err
+	}
+	if width >= original {
+		return "", nil
+	}
+	name := variantFilename(filename, width)
+	if ok, err := store.Exists(ctx, name); err != nil || ok {
+		if err != nil {
+			return "", err
+		}
+		return name, nil
+	}
+
+	img, err := decodeUpload(ctx, store, filename)
+	if err != nil {
+		return "", err
+	}
+	if _, err := writeVariant(ctx, store, filename, img, width); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// removeVariants deletes every resized copy of filename, standard or cached
+func removeVariants(ctx context.Context, store storage.Storage, filename string) error {
+	names, err := store.List(ctx, models.VariantPrefix(filename))
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := store.Delete(ctx, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"kunals-blog-backend/config"
+	"kunals-blog-backend/database"
+	"kunals-blog-backend/jobs"
+	"kunals-blog-backend/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// loadBlogVersion loads the blog and one of its versions, writing a 404 or
+// 403 and returning false when the caller may not touch them
+func loadBlogVersion(c *gin.Context, db *gorm.DB) (*models.Blog, *models.BlogVersion, bool) {
+	var blog models.Blog
+	if err := db.First(&blog, "id = ?", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		return nil, nil, false
+	}
+	if !canEditBlog(c, &blog) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit your own posts"})
+		return nil, nil, false
+	}
+	var version models.BlogVersion
+	if err := db.First(&version, "id = ? AND blog_id = ?", c.Param("versionId"), blog.ID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Version not found"})
+		return nil, nil, false
+	}
+	return &blog, &version, true
+}
+
+// currentVersionID returns the version holding the blog's live content. If
+// there is none yet the live content is snapshotted into history first.
+func currentVersionID(db *gorm.DB, blog *models.Blog) (string, error) {
+	if blog.CurrentVersionID != nil {
+		return *blog.CurrentVersionID, nil
+	}
+	mediaIDs, err := models.BlogMediaIDs(db, blog.ID)
+	if err != nil {
+		return "", err
+	}
+	snapshot := models.BlogVersion{BlogID: blog.ID, Title: blog.Title, Content: blog.Content, ContentFormat: blog.ContentFormat, Language: blog.Language, MediaIDs: mediaIDs, Status: models.VersionStatusHistory}
+	if blog.IsPublished {
+		// Readers have seen this content, so retention keeps it
+		now := time.Now()
+		snapshot.AppliedAt = &now
+	}
+	if err := db.Create(&snapshot).Error; err != nil {
+		return "", err
+	}
+	result := db.Model(&models.Blog{}).
+		Where("id = ? AND current_version_id IS NULL", blog.ID).
+		UpdateColumn("current_version_id", snapshot.ID)
+	if result.Error != nil {
+		return "", result.Error
+	}
+	if result.RowsAffected == 0 {
+		// Another request snapshotted it first; use theirs
+		db.Delete(&snapshot)
+		var current models.Blog
+		if err := db.Select("id, current_version_id").First(&current, "id = ?", blog.ID).Error; err != nil {
+			return "", err
+		}
+		if current.CurrentVersionID == nil {
+			return "", gorm.ErrRecordNotFound
+		}
+		blog.CurrentVersionID = current.CurrentVersionID
+		return *current.CurrentVersionID, nil
+	}
+	blog.CurrentVersionID = &snapshot.ID
+	return snapshot.ID, nil
+}
+
+// ListVersions returns a blog's versions, newest first
+func ListVersions(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	db := database.GetDB()
+	var blog models.Blog
+	if err := db.Select("id, author_id").First(&blog, "id = ?", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Blog not found"})
+		return
+	}
+	if !canEditBlog(c, &blog) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only edit your own posts"})
+		return
+	}
+
+	query := db.Model(&models.BlogVersion{}).Where("blog_id = ?", blog.ID)
+	// Working drafts are per editor; they are listed only when asked for,
+	// and only the caller's own
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+		if status == models.VersionStatusAutosave {
+			query = query.Where("author_id = ?", currentUserID(c))
+		}
+	} else {
+		query = query.Where("status <> ?", models.VersionStatusAutosave)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var versions []models.BlogVersion
+	if err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&versions).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch versions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"versions": versions,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// PreviewVersionPrune lists what the retention job would delete on its next run
+func PreviewVersionPrune(c *gin.Context) {
+	policy := jobs.CurrentVersionRetention()
+	prune, err := jobs.PlanVersionPrune(policy, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to plan version pruning"})
+		return
+	}
+
+	perBlog := map[string]int{}
+	for _, v := range prune {
+		perBlog[v.BlogID]++
+	}
+	total := len(prune)
+	if len(prune) > 500 {
+		prune = prune[:500]
+	}
+	if prune == nil {
+		prune = []models.BlogVersion{}
+	}
+
+	cfg := config.GetConfig()
+	c.JSON(http.StatusOK, gin.H{
+		"enabled":         policy.Enabled(),
+		"keep_all_days":   cfg.VersionKeepAllDays,
+		"keep_daily_days": cfg.VersionKeepDailyDays,
+		"total":           total,
+		"per_blog":        perBlog,
+		"versions":        prune,
+	})
+}
+
+// GetVersion returns a single version
+func GetVersion(c *gin.Context) {
+	_, version, ok := loadBlogVersion(c, database.GetDB())
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"version": version})
+}
+
+// RejectVersion turns down a pending version but keeps it on record
+func RejectVersion(c *gin.Context) {
+	db := database.GetDB()
+	_, version, ok := loadBlogVersion(c, db)
+	if !ok {
+		return
+	}
+	if version.Status != models.VersionStatusPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only pending versions can be rejected"})
+		return
+	}
+
+	version.Status = models.VersionStatusRejected
+	if err := db.Save(version).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reject version"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Version rejected", "version": version})
+}
+
+// DiscardVersion deletes a pending version outright
+func DiscardVersion(c *gin.Context) {
+	db := database.GetDB()
+	_, version, ok := loadBlogVersion(c, db)
+	if !ok {
+		return
+	}
+	if version.Status != models.VersionStatusPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only pending versions can be discarded"})
+		return
+	}
+
+	if err := db.Delete(version).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to discard version"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Version discarded"})
+}
+
+// RestoreVersion puts an earlier snapshot back live. The current content
+// is kept as a history snapshot first, so a restore can itself be undone.
+func RestoreVersion(c *gin.Context) {
+	db := database.GetDB()
+	blog, version, ok := loadBlogVersion(c, db)
+	if !ok {
+		return
+	}
+	if version.Status != models.VersionStatusHistory && version.Status != models.VersionStatusApplied {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only applied or history versions can be restored"})
+		return
+	}
+
+	base, ok := applyBaseRevision(c)
+	if !ok {
+		return
+	}
+	if err := applyToLive(db, blog, version, base); err != nil {
+		if errors.Is(err, models.ErrRevisionConflict) {
+			revisionConflict(c, db, blog.ID)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore version"})
+		return
+	}
+
+	setRevisionETag(c, blog)
+	c.JSON(http.StatusOK, gin.H{"message": "Version restored", "blog": blog})
+}
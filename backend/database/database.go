@@ -5,6 +5,7 @@ import (
 
 	"kunals-blog-backend/config"
 	"kunals-blog-backend/models"
+	"kunals-blog-backend/utils"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -34,14 +35,111 @@ func InitDatabase() {
 		&models.Like{},
 		&models.View{},
 		&models.BlogVersion{},
+		&models.BlogSlug{},
+		&models.Tag{},
+		&models.Category{},
+		&models.RelatedBlog{},
+		&models.Media{},
+		&models.BlogImage{},
 	)
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
+	backfillBlogSlugs()
+	backfillRenderedContent()
+	backfillTextStats()
+	backfillTableOfContents()
+	backfillVersionStatus()
+	backfillVersionUpdatedAt()
+	ensureAutosaveIndex()
+	backfillMediaLibrary()
+	backfillMediaHashes()
+	ensureMediaHashIndex()
+	backfillLinkedMedia()
+	backfillMediaRefCounts()
+	ensureSearchIndex()
+
 	log.Println("Database connected and migrated successfully")
 }
 
+// backfillBlogSlugs gives blogs created before slugs existed a slug
+func backfillBlogSlugs() {
+	var blogs []models.Blog
+	DB.Select("id, title").Where("slug IS NULL OR slug = ''").Find(&blogs)
+	for _, b := range blogs {
+		slug := models.UniqueBlogSlug(DB, b.Title, b.ID)
+		DB.Model(&models.Blog{}).Where("id = ?", b.ID).Update("slug", slug)
+	}
+}
+
+// backfillRenderedContent renders content_html for blogs written before
+// rendering existed. Those were all stored as HTML, and go through the
+// sanitizer like any post saved now.
+func backfillRenderedContent() {
+	var blogs []models.Blog
+	DB.Unscoped().Where("content_html IS NULL").Find(&blogs)
+	for _, b := range blogs {
+		b.ContentFormat = models.ContentFormatHTML
+		if err := b.Render(); err != nil {
+			log.Printf("Failed to render blog %s: %v", b.ID, err)
+			continue
+		}
+		DB.Unscoped().Model(&b).
+			Select("content", "content_format", "content_html", "table_of_contents", "preview", "word_count", "reading_time").
+			UpdateColumns(&b)
+	}
+}
+
+// backfillTextStats computes previews, word counts and reading times for
+// blogs saved before they were derived from the rendered text
+func backfillTextStats() {
+	var blogs []models.Blog
+	DB.Select("id, content_html, excerpt").Where("word_count = 0 AND content_html <> ''").Find(&blogs)
+	for _, b := range blogs {
+		b.RefreshTextStats()
+		DB.Model(&models.Blog{}).Where("id = ?", b.ID).UpdateColumns(map[string]interface{}{
+			"preview":      b.Preview,
+			"word_count":   b.WordCount,
+			"reading_time": b.ReadingTime,
+		})
+	}
+}
+
+// backfillTableOfContents adds heading anchors and a table of contents to
+// blogs rendered before tables of contents existed
+func backfillTableOfContents() {
+	var blogs []models.Blog
+	DB.Select("id, content_html").Where("table_of_contents IS NULL AND content_html <> ''").Find(&blogs)
+	for _, b := range blogs {
+		html, toc := utils.BuildTOC(b.ContentHTML)
+		DB.Model(&b).Select("content_html", "table_of_contents").
+			UpdateColumns(models.Blog{ContentHTML: html, TableOfContents: toc})
+	}
+}
+
+// backfillVersionStatus derives a status for versions saved before they
+// had one. Applied edits and snapshots can't be told apart, so both become
+// history, marked as applied so retention keeps them.
+func backfillVersionStatus() {
+	DB.Exec(`UPDATE blog_versions SET status = CASE WHEN is_pending THEN 'pending' ELSE 'history' END,
+		applied_at = CASE WHEN is_pending THEN NULL ELSE created_at END
+		WHERE status IS NULL OR status = ''`)
+}
+
+// backfillVersionUpdatedAt dates versions saved before they tracked updates
+func backfillVersionUpdatedAt() {
+	DB.Exec("UPDATE blog_versions SET updated_at = created_at WHERE updated_at IS NULL")
+}
+
+// ensureAutosaveIndex allows a single autosave draft per editor per blog,
+// which AutoMigrate can't express as it is a partial index
+func ensureAutosaveIndex() {
+	if err := DB.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_blog_versions_autosave ON blog_versions (blog_id, author_id) WHERE status = 'autosave'").Error; err != nil {
+		log.Println("Failed to create autosave index:", err)
+	}
+}
+
 func GetDB() *gorm.DB {
 	return DB
 }
@@ -0,0 +1,176 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"mime"
+	"path/filepath"
+	"strings"
+
+	"kunals-blog-backend/models"
+	"kunals-blog-backend/storage"
+	"kunals-blog-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+// legacyImages is a row from before images were media references
+type legacyImages struct {
+	ID     string
+	Images string
+}
+
+// backfillMediaLibrary moves the comma-joined image URLs blogs and versions
+// used to store into the media library. Each row's old value is cleared once
+// it has moved, so this only does work once.
+func backfillMediaLibrary() {
+	if DB.Migrator().HasColumn("blogs", "images") {
+		var blogs []legacyImages
+		DB.Table("blogs").Select("id, images").Where("images IS NOT NULL AND images <> ''").Scan(&blogs)
+		for _, b := range blogs {
+			ids := legacyMediaIDs(b.Images)
+			err := DB.Transaction(func(tx *gorm.DB) error {
+				if err := models.SetBlogImages(tx, b.ID, ids); err != nil {
+					return err
+				}
+				return tx.Exec("UPDATE blogs SET images = NULL WHERE id = ?", b.ID).Error
+			})
+			if err != nil {
+				log.Printf("Failed to move images of blog %s: %v", b.ID, err)
+			}
+		}
+	}
+
+	if DB.Migrator().HasColumn("blog_versions", "images") {
+		var versions []legacyImages
+		DB.Table("blog_versions").Select("id, images").Where("images IS NOT NULL AND images <> ''").Scan(&versions)
+		for _, v := range versions {
+			ids, _ := json.Marshal(legacyMediaIDs(v.Images))
+			if err := DB.Exec("UPDATE blog_versions SET media_ids = ?, images = NULL WHERE id = ?", string(ids), v.ID).Error; err != nil {
+				log.Printf("Failed to move images of version %s: %v", v.ID, err)
+			}
+		}
+	}
+}
+
+// legacyMediaIDs finds or creates a media row for each uploaded file in a
+// comma-joined URL list. URLs that don't point at an upload are dropped.
+func legacyMediaIDs(images string) []string {
+	ids := []string{}
+	for _, url := range strings.Split(images, ",") {
+		url = strings.TrimSpace(url)
+		if url == "" {
+			continue
+		}
+		i := strings.LastIndex(url, "/uploads/")
+		name := ""
+		if i >= 0 {
+			name = url[i+len("/uploads/"):]
+		}
+		if name == "" || strings.ContainsAny(name, "/\\") {
+			log.Printf("Dropping image that is not an upload: %s", url)
+			continue
+		}
+
+		var media models.Media
+		if err := DB.Unscoped().Where(models.Media{Filename: name}).Attrs(legacyMedia(name)).FirstOrCreate(&media).Error; err != nil {
+			log.Printf("Failed to add %s to the media library: %v", name, err)
+			continue
+		}
+		ids = append(ids, media.ID)
+	}
+	return ids
+}
+
+// legacyMedia describes an existing upload from the file itself
+func legacyMedia(name string) models.Media {
+	media := models.Media{Filename: name, OriginalName: name, MimeType: mime.TypeByExtension(strings.ToLower(filepath.Ext(name)))}
+	r, err := storage.Default().Get(context.Background(), name)
+	if err != nil {
+		log.Printf("Upload %s is missing from storage", name)
+		return media
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return media
+	}
+	media.Size = int64(len(data))
+	media.Width, media.Height, _ = utils.ImageSize(bytes.NewReader(data))
+	return media
+}
+
+// backfillMediaHashes hashes media stored before uploads were deduplicated.
+// Where old uploads share content only the first keeps the hash, as hashes
+// are unique; the others stay separate files.
+func backfillMediaHashes() {
+	var media []models.Media
+	DB.Unscoped().Select("id, filename").Where("sha256 IS NULL OR sha256 = ''").Order("created_at ASC").Find(&media)
+	for _, m := range media {
+		r, err := storage.Default().Get(context.Background(), m.Filename)
+		if err != nil {
+			log.Printf("Cannot hash media %s: %v", m.ID, err)
+			continue
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, r)
+		r.Close()
+		if err != nil {
+			log.Printf("Cannot hash media %s: %v", m.ID, err)
+			continue
+		}
+		sum := hex.EncodeToString(h.Sum(nil))
+
+		var taken int64
+		DB.Unscoped().Model(&models.Media{}).Where("sha256 = ?", sum).Count(&taken)
+		if taken > 0 {
+			continue
+		}
+		DB.Unscoped().Model(&models.Media{}).Where("id = ?", m.ID).UpdateColumn("sha256", sum)
+	}
+}
+
+// ensureMediaHashIndex keeps one media row per file content. It is a
+// partial index so media that couldn't be hashed doesn't collide.
+func ensureMediaHashIndex() {
+	if err := DB.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_media_sha256 ON media (sha256) WHERE sha256 <> ''").Error; err != nil {
+		log.Println("Failed to create media hash index:", err)
+	}
+}
+
+// backfillLinkedMedia records the media that blogs and versions saved
+// before links were tracked refer to from their content
+func backfillLinkedMedia() {
+	var blogs []models.Blog
+	DB.Unscoped().Select("id, content, content_html").Where("linked_media IS NULL").Find(&blogs)
+	for _, b := range blogs {
+		ids, err := models.ContentMediaIDs(DB, b.Content, b.ContentHTML)
+		if err != nil {
+			log.Printf("Failed to find media linked from blog %s: %v", b.ID, err)
+			continue
+		}
+		DB.Unscoped().Model(&b).Select("linked_media").UpdateColumns(models.Blog{LinkedMedia: ids})
+	}
+
+	var versions []models.BlogVersion
+	DB.Select("id, content").Where("linked_media IS NULL").Find(&versions)
+	for _, v := range versions {
+		ids, err := models.ContentMediaIDs(DB, v.Content)
+		if err != nil {
+			log.Printf("Failed to find media linked from version %s: %v", v.ID, err)
+			continue
+		}
+		DB.Model(&v).Select("linked_media").UpdateColumns(models.BlogVersion{LinkedMedia: ids})
+	}
+}
+
+// backfillMediaRefCounts corrects reference counts that don't match the
+// blogs using each media item, e.g. for media from before counts existed
+func backfillMediaRefCounts() {
+	DB.Exec("UPDATE media SET ref_count = " + models.MediaRefCountSQL + " WHERE ref_count <> " + models.MediaRefCountSQL)
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// searchConfigs maps Blog.Language to a Postgres text search configuration.
+// Languages Postgres has no stemmer for (e.g. Hindi) fall back to "simple".
+var searchConfigs = map[string]string{
+	"english":    "english",
+	"spanish":    "spanish",
+	"french":     "french",
+	"german":     "german",
+	"italian":    "italian",
+	"portuguese": "portuguese",
+	"dutch":      "dutch",
+	"russian":    "russian",
+	"swedish":    "swedish",
+	"norwegian":  "norwegian",
+	"danish":     "danish",
+	"finnish":    "finnish",
+	"turkish":    "turkish",
+}
+
+// SearchConfig returns the text search configuration for a blog language
+func SearchConfig(language string) string {
+	if cfg, ok := searchConfigs[strings.ToLower(strings.TrimSpace(language))]; ok {
+		return cfg
+	}
+	return "simple"
+}
+
+// The title is weighted A and the tag-stripped rendered content B so title
+// hits rank higher
+const refreshSearchVectorSQL = `UPDATE blogs SET
+	search_config = ?::regconfig,
+	search_vector = setweight(to_tsvector(?::regconfig, coalesce(title, '')), 'A') ||
+		setweight(to_tsvector(?::regconfig, regexp_replace(coalesce(content_html, content, ''), '<[^>]*>', ' ', 'g')), 'B')
+	WHERE id = ?`
+
+// RefreshSearchVector recomputes the search document of a single blog.
+// Call it whenever title, content or language change.
+func RefreshSearchVector(db *gorm.DB, blogID string) error {
+	var language string
+	if err := db.Table("blogs").Select("language").Where("id = ?", blogID).Scan(&language).Error; err != nil {
+		return err
+	}
+	cfg := SearchConfig(language)
+	return db.Exec(refreshSearchVectorSQL, cfg, cfg, cfg, blogID).Error
+}
+
+// ensureSearchIndex adds the tsvector column and its GIN index, which
+// AutoMigrate can't express, and indexes blogs that have no vector yet.
+func ensureSearchIndex() {
+	statements := []string{
+		`ALTER TABLE blogs ADD COLUMN IF NOT EXISTS search_config regconfig NOT NULL DEFAULT 'simple'`,
+		`ALTER TABLE blogs ADD COLUMN IF NOT EXISTS search_vector tsvector`,
+		`CREATE INDEX IF NOT EXISTS idx_blogs_search_vector ON blogs USING GIN (search_vector)`,
+	}
+	for _, stmt := range statements {
+		if err := DB.Exec(stmt).Error; err != nil {
+			log.Fatal("Failed to set up search index:", err)
+		}
+	}
+
+	var ids []string
+	DB.Table("blogs").Where("search_vector IS NULL").Pluck("id", &ids)
+	for _, id := range ids {
+		if err := RefreshSearchVector(DB, id); err != nil {
+			log.Printf("Failed to index blog %s for search: %v", id, err)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"kunals-blog-backend/config"
+)
+
+// Start launches the background jobs. All job state lives in the database,
+// so nothing is lost across restarts.
+func Start() {
+	cfg := config.GetConfig()
+	go every("publish scheduled blogs", cfg.SchedulerInterval, PublishDueBlogs)
+	go relatedLoop(cfg.RelatedInterval)
+	if cfg.TrashRetentionDays > 0 {
+		retention := time.Duration(cfg.TrashRetentionDays) * 24 * time.Hour
+		go every("purge expired trash", time.Hour, func() error {
+			return PurgeExpiredTrash(retention)
+		})
+	}
+	go every("collect deleted media", time.Hour, func() error {
+		_, err := CollectDeletedMedia()
+		return err
+	})
+	if policy := CurrentVersionRetention(); policy.Enabled() {
+		go every("prune versions", cfg.VersionPruneInterval, func() error {
+			_, err := PruneVersions(policy)
+			return err
+		})
+	}
+}
+
+// every runs fn straight away, catching up on work that came due while the
+// server was down, and then once per interval.
+func every(name string, interval time.Duration, fn func() error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := fn(); err != nil {
+			log.Printf("Job %q failed: %v", name, err)
+		}
+		<-ticker.C
+	}
+}
@@ -0,0 +1,60 @@
+package jobs
+
+import (
+	"context"
+
+	"kunals-blog-backend/database"
+	"kunals-blog-backend/models"
+	"kunals-blog-backend/storage"
+)
+
+// unusedMediaSQL matches media no blog and no version shows or links to,
+// so restoring any version later still finds its images
+const unusedMediaSQL = "ref_count = 0 AND " + models.MediaRefCountSQL + " = 0" +
+	" AND NOT EXISTS (SELECT 1 FROM blog_versions WHERE blog_versions.media_ids LIKE '%\"' || media.id || '\"%'" +
+	" OR blog_versions.linked_media LIKE '%\"' || media.id || '\"%')"
+
+// CollectDeletedMedia removes media deleted from the library once nothing
+// uses it any more, along with its files. Given IDs, only those are
+// checked. It returns how many were removed.
+func CollectDeletedMedia(ids ...string) (int, error) {
+	db := database.GetDB()
+	query := db.Unscoped().Where("deleted_at IS NOT NULL").Where(unusedMediaSQL)
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+	var media []models.Media
+	if err := query.Select("id, filename").Find(&media).Error; err != nil {
+		return 0, err
+	}
+
+	ctx := context.Background()
+	store := storage.Default()
+	removed := 0
+	for _, m := range media {
+		// The row goes first and only if still deleted and unused, so a blog
+		// picking the media up or an upload restoring it meanwhile keeps it
+		// and its files
+		result := db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", m.ID).Where(unusedMediaSQL).Delete(&models.Media{})
+		if result.Error != nil {
+			return removed, result.Error
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		if err := store.Delete(ctx, m.Filename); err != nil {
+			return removed, err
+		}
+		variants, err := store.List(ctx, models.VariantPrefix(m.Filename))
+		if err != nil {
+			return removed, err
+		}
+		for _, name := range variants {
+			if err := store.Delete(ctx, name); err != nil {
+				return removed, err
+			}
+		}
+		removed++
+	}
+	return removed, nil
+}
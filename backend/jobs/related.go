@@ -0,0 +1,172 @@
+package jobs
+
+import (
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"kunals-blog-backend/database"
+	"kunals-blog-backend/models"
+	"kunals-blog-backend/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	relatedPerBlog = 6
+	// Title words count for more than words in the body
+	titleTermWeight = 3.0
+	// Score = shared terms + same language bonus + recency bonus
+	languageBonus  = 0.15
+	recencyBonus   = 0.10
+	recencyHalfAge = 180 * 24 * time.Hour
+	// Changes within this window are folded into one recompute
+	relatedDebounce = 5 * time.Second
+)
+
+var relatedRefresh = make(chan struct{}, 1)
+
+// RequestRelatedRefresh asks for related posts to be recomputed soon. It
+// never blocks; requests made while one is pending are merged.
+func RequestRelatedRefresh() {
+	select {
+	case relatedRefresh <- struct{}{}:
+	default:
+	}
+}
+
+// relatedLoop recomputes on start, on every interval and shortly after a
+// refresh is requested
+func relatedLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := RecomputeRelated(); err != nil {
+			log.Printf("Job %q failed: %v", "related posts", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-relatedRefresh:
+			time.Sleep(relatedDebounce)
+		}
+	}
+}
+
+type relatedDoc struct {
+	id        string
+	language  string
+	published time.Time
+	weights   map[string]float64
+	norm      float64
+}
+
+// RecomputeRelated scores every pair of published posts and stores the
+// best matches for each
+func RecomputeRelated() error {
+	db := database.GetDB()
+
+	var blogs []models.Blog
+	err := db.Select("id, title, content_html, language, published_at, created_at").
+		Where("is_published = ?", true).
+		Find(&blogs).Error
+	if err != nil {
+		return err
+	}
+
+	docs := buildRelatedDocs(blogs)
+	now := time.Now()
+
+	var rows []models.RelatedBlog
+	for i, doc := range docs {
+		type scored struct {
+			id    string
+			score float64
+		}
+		var candidates []scored
+		for j, other := range docs {
+			if i == j {
+				continue
+			}
+			similarity := cosine(doc, other)
+			if similarity == 0 {
+				continue
+			}
+			score := similarity
+			if doc.language == other.language {
+				score += languageBonus
+			}
+			age := now.Sub(other.published)
+			score += recencyBonus * math.Pow(0.5, float64(age)/float64(recencyHalfAge))
+			candidates = append(candidates, scored{id: other.id, score: score})
+		}
+
+		sort.Slice(candidates, func(a, b int) bool { return candidates[a].score > candidates[b].score })
+		if len(candidates) > relatedPerBlog {
+			candidates = candidates[:relatedPerBlog]
+		}
+		for rank, cand := range candidates {
+			rows = append(rows, models.RelatedBlog{BlogID: doc.id, RelatedID: cand.id, Score: cand.score, Rank: rank + 1})
+		}
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.RelatedBlog{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(rows, 500).Error
+	})
+}
+
+// buildRelatedDocs turns posts into TF-IDF weighted term vectors
+func buildRelatedDocs(blogs []models.Blog) []relatedDoc {
+	docs := make([]relatedDoc, len(blogs))
+	docFreq := map[string]int{}
+	for i, blog := range blogs {
+		tf := map[string]float64{}
+		for _, term := range utils.Terms(blog.Title) {
+			tf[term] += titleTermWeight
+		}
+		for _, term := range utils.Terms(utils.PlainText(blog.ContentHTML)) {
+			tf[term]++
+		}
+		for term := range tf {
+			docFreq[term]++
+		}
+
+		published := blog.CreatedAt
+		if blog.PublishedAt != nil {
+			published = *blog.PublishedAt
+		}
+		docs[i] = relatedDoc{id: blog.ID, language: blog.Language, published: published, weights: tf}
+	}
+
+	n := float64(len(blogs))
+	for i := range docs {
+		var sum float64
+		for term, tf := range docs[i].weights {
+			w := (1 + math.Log(tf)) * math.Log(1+n/float64(docFreq[term]))
+			docs[i].weights[term] = w
+			sum += w * w
+		}
+		docs[i].norm = math.Sqrt(sum)
+	}
+	return docs
+}
+
+func cosine(a, b relatedDoc) float64 {
+	if a.norm == 0 || b.norm == 0 {
+		return 0
+	}
+	if len(a.weights) > len(b.weights) {
+		a, b = b, a
+	}
+	var dot float64
+	for term, w := range a.weights {
+		dot += w * b.weights[term]
+	}
+	return dot / (a.norm * b.norm)
+}
@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"kunals-blog-backend/database"
+	"kunals-blog-backend/models"
+)
+
+// PublishDueBlogs publishes scheduled blogs whose publish time has passed
+func PublishDueBlogs() error {
+	db := database.GetDB()
+
+	var due []models.Blog
+	err := db.Select("id, scheduled_at").
+		Where("is_published = ? AND scheduled_at IS NOT NULL AND scheduled_at <= ?", false, time.Now()).
+		Find(&due).Error
+	if err != nil {
+		return err
+	}
+
+	for _, blog := range due {
+		// Guard on the scheduled state so another instance can't publish twice
+		result := db.Model(&models.Blog{}).
+			Where("id = ? AND is_published = ? AND scheduled_at IS NOT NULL", blog.ID, false).
+			Updates(map[string]interface{}{
+				"is_published": true,
+				"published_at": *blog.ScheduledAt,
+				"scheduled_at": nil,
+			})
+		if result.Error != nil {
+			log.Printf("Failed to publish scheduled blog %s: %v", blog.ID, result.Error)
+			continue
+		}
+		if result.RowsAffected > 0 {
+			log.Printf("Published scheduled blog %s", blog.ID)
+			RequestRelatedRefresh()
+		}
+	}
+	return nil
+}
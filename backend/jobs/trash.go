@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"time"
+
+	"kunals-blog-backend/database"
+	"kunals-blog-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// PurgeExpiredTrash permanently deletes blogs that have been in the trash
+// for longer than the retention period
+func PurgeExpiredTrash(retention time.Duration) error {
+	db := database.GetDB()
+
+	var ids []string
+	err := db.Unscoped().Model(&models.Blog{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", time.Now().Add(-retention)).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			return models.PurgeBlog(tx, id)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package jobs
+
+import (
+	"fmt"
+	"time"
+
+	"kunals-blog-backend/config"
+	"kunals-blog-backend/database"
+	"kunals-blog-backend/models"
+)
+
+// VersionRetention decides which old versions are thinned out
+type VersionRetention struct {
+	KeepAll   time.Duration // Everything younger than this is kept
+	KeepDaily time.Duration // Then one a day up to this age, then one a week
+}
+
+// prunableStatuses are the versions retention may remove. Pending edits,
+// working drafts and versions that went live are never pruned, nor is
+// anything with applied_at set: a history snapshot readers have seen, or
+// one restored since.
+var prunableStatuses = []string{models.VersionStatusHistory, models.VersionStatusRejected, models.VersionStatusMerged}
+
+// CurrentVersionRetention reads the retention policy from the config
+func CurrentVersionRetention() VersionRetention {
+	cfg := config.GetConfig()
+	day := 24 * time.Hour
+	return VersionRetention{
+		KeepAll:   time.Duration(cfg.VersionKeepAllDays) * day,
+		KeepDaily: time.Duration(cfg.VersionKeepDailyDays) * day,
+	}
+}
+
+// Enabled is false when the policy keeps everything
+func (p VersionRetention) Enabled() bool {
+	return p.KeepAll > 0
+}
+
+// PlanVersionPrune lists the versions the policy would remove, newest first
+// per blog. Within each day (or week, past KeepDaily) only the newest
+// version is kept. The live version of every blog and the versions pending
+// edits are based on are always kept.
+func PlanVersionPrune(policy VersionRetention, now time.Time) ([]models.BlogVersion, error) {
+	if !policy.Enabled() {
+		return nil, nil
+	}
+	db := database.GetDB()
+
+	protected := map[string]bool{}
+	var ids []string
+	if err := db.Unscoped().Model(&models.Blog{}).Where("current_version_id IS NOT NULL").Pluck("current_version_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		protected[id] = true
+	}
+	ids = nil
+	err := db.Model(&models.BlogVersion{}).
+		Where("status IN ? AND base_version_id IS NOT NULL", []string{models.VersionStatusPending, models.VersionStatusAutosave}).
+		Pluck("base_version_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		protected[id] = true
+	}
+
+	var candidates []models.BlogVersion
+	err = db.Select("id, blog_id, title, status, created_at").
+		Where("status IN ? AND applied_at IS NULL AND created_at < ?", prunableStatuses, now.Add(-policy.KeepAll)).
+		Order("blog_id, created_at DESC").
+		Find(&candidates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var prune []models.BlogVersion
+	kept := map[string]bool{}
+	for _, v := range candidates {
+		bucket := v.BlogID + "/" + v.CreatedAt.UTC().Format("2006-01-02")
+		if now.Sub(v.CreatedAt) > policy.KeepDaily {
+			year, week := v.CreatedAt.UTC().ISOWeek()
+			bucket = fmt.Sprintf("%s/%d-W%02d", v.BlogID, year, week)
+		}
+		if protected[v.ID] || !kept[bucket] {
+			kept[bucket] = true
+			continue
+		}
+		prune = append(prune, v)
+	}
+	return prune, nil
+}
+
+// PruneVersions deletes the versions the policy no longer keeps and
+// returns how many were removed
+func PruneVersions(policy VersionRetention) (int, error) {
+	prune, err := PlanVersionPrune(policy, time.Now())
+	if err != nil || len(prune) == 0 {
+		return 0, err
+	}
+
+	db := database.GetDB()
+	ids := make([]string, len(prune))
+	for i, v := range prune {
+		ids[i] = v.ID
+	}
+	removed := 0
+	for start := 0; start < len(ids); start += 500 {
+		end := start + 500
+		if end > len(ids) {
+			end = len(ids)
+		}
+		// Re-check protection in case a version went live or gained a
+		// pending edit since the plan was made
+		result := db.Where("id IN ? AND applied_at IS NULL", ids[start:end]).
+			Where("id NOT IN (SELECT current_version_id FROM blogs WHERE current_version_id IS NOT NULL)").
+			Where("id NOT IN (SELECT base_version_id FROM blog_versions WHERE base_version_id IS NOT NULL AND status IN ?)",
+				[]string{models.VersionStatusPending, models.VersionStatusAutosave}).
+			Delete(&models.BlogVersion{})
+		if result.Error != nil {
+			return removed, result.Error
+		}
+		removed += int(result.RowsAffected)
+	}
+	return removed, nil
+}
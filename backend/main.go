@@ -2,18 +2,38 @@ package main
 
 import (
 	"log"
+	"os"
 
+	"kunals-blog-backend/commands"
 	"kunals-blog-backend/config"
 	"kunals-blog-backend/database"
+	"kunals-blog-backend/jobs"
 	"kunals-blog-backend/routes"
+	"kunals-blog-backend/storage"
 
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	// Uploads must be reachable before the database migrations run
+	if err := storage.Init(); err != nil {
+		log.Fatal("Failed to open upload storage:", err)
+	}
+
 	// Initialize database
 	database.InitDatabase()
 
+	// One-off maintenance commands, e.g. "server sanitize"
+	if len(os.Args) > 1 {
+		if err := commands.Run(os.Args[1], os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Start background jobs (scheduled publishing)
+	jobs.Start()
+
 	// Initialize router
 	router := gin.Default()
 
@@ -21,7 +41,8 @@ func main() {
 	router.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match")
+		c.Header("Access-Control-Expose-Headers", "ETag")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -4,6 +4,8 @@ import (
 	"net/http"
 	"strings"
 
+	"kunals-blog-backend/database"
+	"kunals-blog-backend/models"
 	"kunals-blog-backend/utils"
 
 	"github.com/gin-gonic/gin"
@@ -34,20 +36,33 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Roles come from the account rather than the token, so a change of
+		// role applies straight away instead of when the token expires
+		var user models.User
+		if err := database.GetDB().Select("id, username, is_admin, roles").First(&user, "id = ?", claims.UserID).Error; err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			c.Abort()
+			return
+		}
+
 		// Set user info in context
-		c.Set("user_id", claims.UserID)
-		c.Set("username", claims.Username)
-		c.Set("is_admin", claims.IsAdmin)
+		c.Set("user_id", user.ID)
+		c.Set("username", user.Username)
+		c.Set("is_admin", user.IsAdmin)
+		c.Set("roles", user.EffectiveRoles())
 
 		c.Next()
 	}
 }
 
-func AdminMiddleware() gin.HandlerFunc {
+// RequirePermission lets the request through only if one of the user's
+// roles grants perm. It must run after AuthMiddleware.
+func RequirePermission(perm string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		isAdmin, exists := c.Get("is_admin")
-		if !exists || !isAdmin.(bool) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		rolesVal, _ := c.Get("roles")
+		roles, _ := rolesVal.([]string)
+		if !models.HasPermission(roles, perm) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Permission denied"})
 			c.Abort()
 			return
 		}
@@ -0,0 +1,15 @@
+package models
+
+// Author is the public view of a user who writes posts. It reads from the
+// users table but leaves out account fields.
+type Author struct {
+	ID          string `json:"id"`
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+	Bio         string `json:"bio"`
+	AvatarURL   string `json:"avatar_url"`
+}
+
+func (Author) TableName() string {
+	return "users"
+}
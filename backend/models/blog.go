@@ -3,36 +3,77 @@ package models
 import (
 	"time"
 
+	"kunals-blog-backend/utils"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type Blog struct {
-	ID            string     `json:"id" gorm:"primaryKey"`
-	Title         string     `json:"title" gorm:"not null"`
-	Content       string     `json:"content" gorm:"type:text"`
-	Preview       string     `json:"preview" gorm:"size:500"`
-	Language      string     `json:"language" gorm:"default:'english'"`
-	Images        string     `json:"images" gorm:"type:text"` // JSON array of image URLs
-	IsPublished   bool       `json:"is_published" gorm:"default:false"`
-	PublishedAt   *time.Time `json:"published_at"`
-	CustomDate    *time.Time `json:"custom_date"` // Admin can set custom publish date
-	LikesCount    int        `json:"likes_count" gorm:"default:0"`
-	CommentsCount int        `json:"comments_count" gorm:"default:0"`
-	ViewsCount    int        `json:"views_count" gorm:"default:0"`
-	CreatedAt     time.Time  `json:"created_at"`
-	UpdatedAt     time.Time  `json:"updated_at"`
+	ID                 string           `json:"id" gorm:"primaryKey"`
+	Title              string           `json:"title" gorm:"not null"`
+	Slug               string           `json:"slug" gorm:"uniqueIndex"`
+	SlugLocked         bool             `json:"slug_locked" gorm:"default:false"`                             // Set when the admin picked the slug by hand
+	Content            string           `json:"content" gorm:"type:text"`                                     // Source as written, HTML or Markdown
+	ContentFormat      string           `json:"content_format" gorm:"default:'html'"`                         // html | markdown
+	ContentHTML        string           `json:"content_html" gorm:"type:text"`                                // Rendered, sanitized HTML
+	TableOfContents    []utils.TOCEntry `json:"table_of_contents,omitempty" gorm:"serializer:json;type:text"` // Built from the headings in ContentHTML
+	LinkedMedia        []string         `json:"-" gorm:"serializer:json;type:text"`                           // IDs of media the content links to
+	Preview            string           `json:"preview" gorm:"size:500"`
+	Excerpt            string           `json:"excerpt" gorm:"size:500"` // Manual summary; overrides the generated preview
+	WordCount          int              `json:"word_count" gorm:"default:0"`
+	ReadingTime        int              `json:"reading_time" gorm:"default:0"` // Minutes
+	Language           string           `json:"language" gorm:"default:'english'"`
+	IsPublished        bool             `json:"is_published" gorm:"default:false"`
+	PublishedAt        *time.Time       `json:"published_at"`
+	CustomDate         *time.Time       `json:"custom_date"`               // Admin can set custom publish date
+	ScheduledAt        *time.Time       `json:"scheduled_at" gorm:"index"` // Future publish time picked up by the scheduler
+	Status             string           `json:"status" gorm:"-"`           // draft | scheduled | published, derived on load
+	AuthorID           *string          `json:"author_id" gorm:"index"`
+	TranslationGroupID *string          `json:"translation_group_id" gorm:"index"`    // Shared by translations of one post; the original's ID
+	IsPinned           bool             `json:"is_pinned" gorm:"default:false;index"` // Kept at the top of listings
+	PinOrder           int              `json:"pin_order" gorm:"default:0"`           // Lower comes first among pinned posts
+	PinnedUntil        *time.Time       `json:"pinned_until"`                         // Pin lapses after this, if set
+	IsFeatured         bool             `json:"is_featured" gorm:"default:false;index"`
+	FeatureOrder       int              `json:"feature_order" gorm:"default:0"`
+	FeaturedUntil      *time.Time       `json:"featured_until"`
+	Revision           int              `json:"revision" gorm:"not null;default:1"` // Bumped on every edit; sent as the ETag
+	CurrentVersionID   *string          `json:"current_version_id"`                 // Version whose content is live
+	LikesCount         int              `json:"likes_count" gorm:"default:0"`
+	CommentsCount      int              `json:"comments_count" gorm:"default:0"`
+	ViewsCount         int              `json:"views_count" gorm:"default:0"`
+	CreatedAt          time.Time        `json:"created_at"`
+	UpdatedAt          time.Time        `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt   `json:"deleted_at" gorm:"index"` // Set while the blog is in the trash
 
 	// Relations
-	Comments []Comment `json:"comments,omitempty" gorm:"foreignKey:BlogID"`
-	Likes    []Like    `json:"likes,omitempty" gorm:"foreignKey:BlogID"`
-	Views    []View    `json:"views,omitempty" gorm:"foreignKey:BlogID"`
+	Author     *Author     `json:"author,omitempty" gorm:"foreignKey:AuthorID"`
+	Comments   []Comment   `json:"comments,omitempty" gorm:"foreignKey:BlogID"`
+	Likes      []Like      `json:"likes,omitempty" gorm:"foreignKey:BlogID"`
+	Views      []View      `json:"views,omitempty" gorm:"foreignKey:BlogID"`
+	Tags       []Tag       `json:"tags,omitempty" gorm:"many2many:blog_tags;"`
+	Categories []Category  `json:"categories,omitempty" gorm:"many2many:blog_categories;"`
+	Images     []BlogImage `json:"images,omitempty" gorm:"foreignKey:BlogID"`
 }
 
+const (
+	ContentFormatHTML     = "html"
+	ContentFormatMarkdown = "markdown"
+)
+
+const (
+	BlogStatusDraft     = "draft"
+	BlogStatusScheduled = "scheduled"
+	BlogStatusPublished = "published"
+)
+
 func (b *Blog) BeforeCreate(tx *gorm.DB) error {
 	if b.ID == "" {
 		b.ID = uuid.New().String()
 	}
+	if b.Slug == "" {
+		b.Slug = UniqueBlogSlug(tx, b.Title, b.ID)
+	}
 	return nil
 }
 
@@ -43,3 +84,24 @@ func (b *Blog) BeforeUpdate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+func (b *Blog) AfterFind(tx *gorm.DB) error {
+	b.setStatus()
+	return nil
+}
+
+func (b *Blog) AfterSave(tx *gorm.DB) error {
+	b.setStatus()
+	return nil
+}
+
+func (b *Blog) setStatus() {
+	switch {
+	case b.IsPublished:
+		b.Status = BlogStatusPublished
+	case b.ScheduledAt != nil:
+		b.Status = BlogStatusScheduled
+	default:
+		b.Status = BlogStatusDraft
+	}
+}
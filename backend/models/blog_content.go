@@ -0,0 +1,68 @@
+package models
+
+import (
+	"errors"
+	"strings"
+
+	"kunals-blog-backend/utils"
+)
+
+var ErrUnknownContentFormat = errors.New("unknown content format")
+
+// NormalizeContentFormat defaults an empty format to html and rejects
+// anything other than html or markdown
+func NormalizeContentFormat(format string) (string, error) {
+	switch format {
+	case "", ContentFormatHTML:
+		return ContentFormatHTML, nil
+	case ContentFormatMarkdown:
+		return ContentFormatMarkdown, nil
+	}
+	return "", ErrUnknownContentFormat
+}
+
+// RenderContent converts a source in the given format to sanitized HTML
+func RenderContent(format, source string) (string, error) {
+	if format == ContentFormatMarkdown {
+		return utils.RenderMarkdown(source)
+	}
+	return utils.SanitizePost(source), nil
+}
+
+// SanitizeSource cleans an HTML source in place; Markdown sources are
+// left as written and only their rendered output is sanitized
+func SanitizeSource(format, source string) string {
+	if format == ContentFormatMarkdown {
+		return source
+	}
+	return utils.SanitizePost(source)
+}
+
+// Render sanitizes Content and refreshes ContentHTML, the table of contents
+// and the preview from it.
+// Call it whenever Content or ContentFormat change.
+func (b *Blog) Render() error {
+	b.Content = SanitizeSource(b.ContentFormat, b.Content)
+	rendered, err := RenderContent(b.ContentFormat, b.Content)
+	if err != nil {
+		return err
+	}
+	b.ContentHTML, b.TableOfContents = utils.BuildTOC(rendered)
+	b.RefreshTextStats()
+	return nil
+}
+
+const previewRunes = 200
+
+// RefreshTextStats derives Preview, WordCount and ReadingTime from the
+// plain text of ContentHTML. A manual Excerpt is used as the preview as is.
+func (b *Blog) RefreshTextStats() {
+	text := utils.PlainText(b.ContentHTML)
+	b.WordCount = utils.WordCount(text)
+	b.ReadingTime = utils.ReadingMinutes(b.WordCount)
+	if excerpt := strings.TrimSpace(b.Excerpt); excerpt != "" {
+		b.Preview = excerpt
+		return
+	}
+	b.Preview = utils.Truncate(text, previewRunes)
+}
@@ -0,0 +1,45 @@
+package models
+
+import "gorm.io/gorm"
+
+// purgeStatements delete everything that refers to a blog
+var purgeStatements = []string{
+	"DELETE FROM comments WHERE blog_id = @id",
+	"DELETE FROM likes WHERE blog_id = @id",
+	"DELETE FROM views WHERE blog_id = @id",
+	"DELETE FROM blog_versions WHERE blog_id = @id",
+	"DELETE FROM blog_slugs WHERE blog_id = @id",
+	"DELETE FROM blog_tags WHERE blog_id = @id",
+	"DELETE FROM blog_categories WHERE blog_id = @id",
+	"DELETE FROM blog_images WHERE blog_id = @id",
+	"DELETE FROM related_blogs WHERE blog_id = @id OR related_id = @id",
+}
+
+// PurgeBlog permanently removes a blog and every row that refers to it. Run
+// it inside a transaction so a failure leaves the blog untouched.
+func PurgeBlog(tx *gorm.DB, blogID string) error {
+	mediaIDs, err := BlogMediaIDs(tx, blogID)
+	if err != nil {
+		return err
+	}
+	var blog Blog
+	if err := tx.Unscoped().Select("id, linked_media").First(&blog, "id = ?", blogID).Error; err != nil {
+		return err
+	}
+	mediaIDs = append(mediaIDs, blog.LinkedMedia...)
+	args := map[string]interface{}{"id": blogID}
+	for _, stmt := range purgeStatements {
+		if err := tx.Exec(stmt, args).Error; err != nil {
+			return err
+		}
+	}
+
+	result := tx.Unscoped().Delete(&Blog{}, "id = ?", blogID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return RecountMediaRefs(tx, mediaIDs)
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrRevisionConflict means the blog changed since the caller loaded it
+var ErrRevisionConflict = errors.New("blog revision conflict")
+
+// revisionColumns are what an edit writes: the content, what is derived
+// from it and the post's metadata. Counters, publishing and pin or feature
+// state have writers of their own that a revision mustn't undo.
+var revisionColumns = []string{
+	"title", "slug", "slug_locked", "content", "content_format", "content_html",
+	"table_of_contents", "linked_media", "preview", "excerpt", "word_count",
+	"reading_time", "language", "custom_date", "current_version_id",
+	"revision", "updated_at",
+}
+
+// SaveRevision writes the blog's edited columns only if it is still at
+// revision base, and bumps the revision. Unlike Save it never falls back to
+// an insert, so a lost race shows up as ErrRevisionConflict instead of
+// overwriting.
+func (b *Blog) SaveRevision(tx *gorm.DB, base int) error {
+	b.Revision = base + 1
+	result := tx.Model(b).Where("revision = ?", base).
+		Select(revisionColumns).
+		Updates(b)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ErrRevisionConflict
+	}
+	if result.Error != nil {
+		b.Revision = base
+		return result.Error
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"kunals-blog-backend/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BlogSlug keeps slugs a blog used to have so old links keep resolving
+type BlogSlug struct {
+	ID        string    `json:"id" gorm:"primaryKey"`
+	BlogID    string    `json:"blog_id" gorm:"index;not null"`
+	Slug      string    `json:"slug" gorm:"uniqueIndex;not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (s *BlogSlug) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// UniqueBlogSlug slugifies value and appends a numeric suffix until the slug
+// is not used by another blog, either currently or in its slug history.
+// Blogs in the trash keep their slug so they can be restored.
+func UniqueBlogSlug(tx *gorm.DB, value, blogID string) string {
+	base := utils.Slugify(value)
+	candidate := base
+	for i := 2; ; i++ {
+		var taken int64
+		tx.Unscoped().Model(&Blog{}).Where("slug = ? AND id <> ?", candidate, blogID).Count(&taken)
+		if taken == 0 {
+			tx.Model(&BlogSlug{}).Where("slug = ? AND blog_id <> ?", candidate, blogID).Count(&taken)
+		}
+		if taken == 0 {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s-%d", base, i)
+	}
+}
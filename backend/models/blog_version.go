@@ -8,21 +8,53 @@ import (
 )
 
 type BlogVersion struct {
-	ID        string    `json:"id" gorm:"primaryKey"`
-	BlogID    string    `json:"blog_id" gorm:"index;not null"`
-	Title     string    `json:"title"`
-	Content   string    `json:"content" gorm:"type:text"`
-	Language  string    `json:"language"`
-	Images    string    `json:"images" gorm:"type:text"`
-	IsPending bool      `json:"is_pending" gorm:"default:false"`
-	CreatedAt time.Time `json:"created_at"`
+	ID            string     `json:"id" gorm:"primaryKey"`
+	BlogID        string     `json:"blog_id" gorm:"index;not null"`
+	Title         string     `json:"title"`
+	Content       string     `json:"content" gorm:"type:text"`
+	ContentFormat string     `json:"content_format" gorm:"default:'html'"`
+	Language      string     `json:"language"`
+	MediaIDs      []string   `json:"media_ids" gorm:"serializer:json;type:text"` // Images, in order
+	LinkedMedia   []string   `json:"-" gorm:"serializer:json;type:text"`         // IDs of media the content links to
+	BaseVersionID *string    `json:"base_version_id" gorm:"index"`               // Version that was live when this edit started
+	AuthorID      *string    `json:"author_id" gorm:"index"`                     // Editor who saved it
+	Message       string     `json:"message" gorm:"size:500"`                    // Optional note describing the change
+	Status        string     `json:"status" gorm:"size:20;index"`                // pending | applied | rejected | history | merged | autosave
+	AppliedAt     *time.Time `json:"applied_at"`                                 // Went live, or was snapshotted from a published post; never pruned
+	IsPending     bool       `json:"is_pending" gorm:"default:false"`            // Mirrors Status for older clients
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
 
 	Blog Blog `json:"blog,omitempty" gorm:"foreignKey:BlogID"`
 }
 
+const (
+	VersionStatusPending  = "pending"  // Proposed edit waiting to be applied
+	VersionStatusApplied  = "applied"  // Pending edit that went live
+	VersionStatusRejected = "rejected" // Pending edit turned down by an editor
+	VersionStatusHistory  = "history"  // Snapshot of the live blog taken before a change
+	VersionStatusMerged   = "merged"   // Pending edit folded into a merged version
+	VersionStatusAutosave = "autosave" // Working draft, one per editor per blog, overwritten on every autosave
+)
+
 func (v *BlogVersion) BeforeCreate(tx *gorm.DB) error {
 	if v.ID == "" {
 		v.ID = uuid.New().String()
 	}
 	return nil
 }
+
+func (v *BlogVersion) BeforeSave(tx *gorm.DB) error {
+	if v.Status == "" {
+		v.Status = VersionStatusPending
+	}
+	v.IsPending = v.Status == VersionStatusPending
+	// Media the content links to is kept while any version does, so
+	// restoring an old version never brings back a broken image
+	ids, err := ContentMediaIDs(tx, v.Content)
+	if err != nil {
+		return err
+	}
+	v.LinkedMedia = ids
+	return nil
+}
@@ -0,0 +1,234 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Media is an uploaded file. Files with the same content are stored once
+// and shared; a file deleted from the library stays until nothing uses it.
+type Media struct {
+	ID           string         `json:"id" gorm:"primaryKey"`
+	Filename     string         `json:"filename" gorm:"uniqueIndex;not null"` // Name in storage, served under /uploads/
+	SHA256       string         `json:"sha256" gorm:"column:sha256;size:64"`  // Hash of the stored file; unique when set
+	OriginalName string         `json:"original_name"`                        // Name the file was uploaded with
+	Size         int64          `json:"size"`                                 // Bytes
+	MimeType     string         `json:"mime_type" gorm:"size:100;index"`
+	Width        int            `json:"width"`
+	Height       int            `json:"height"`
+	AltText      string         `json:"alt_text" gorm:"size:500"`
+	Caption      string         `json:"caption" gorm:"type:text"`
+	UploaderID   *string        `json:"uploader_id" gorm:"index"`
+	Variants     []ImageVariant `json:"variants" gorm:"serializer:json;type:text"` // Resized copies, narrowest first
+	RefCount     int            `json:"ref_count" gorm:"not null;default:0"`       // Blogs showing it, trashed ones included
+	URL          string         `json:"url" gorm:"-"`                              // Derived on load
+	SrcSet       string         `json:"srcset" gorm:"-"`                           // Variants and original, ready for <img srcset>
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"deleted_at" gorm:"index"` // Removed from the library, files kept while in use
+
+	Uploader *Author `json:"uploader,omitempty" gorm:"foreignKey:UploaderID"`
+}
+
+// ImageVariant is a resized copy of an uploaded image
+type ImageVariant struct {
+	Name     string `json:"name"` // thumb | medium | large
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	Filename string `json:"filename"`
+	URL      string `json:"url"`
+}
+
+func (Media) TableName() string {
+	return "media"
+}
+
+// BlogImage places a media item in a blog's list of images
+type BlogImage struct {
+	BlogID   string `json:"-" gorm:"primaryKey"`
+	MediaID  string `json:"media_id" gorm:"primaryKey"`
+	Position int    `json:"position"` // Order within the blog, from 0
+
+	Media *Media `json:"media,omitempty" gorm:"foreignKey:MediaID"`
+}
+
+func (m *Media) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == "" {
+		m.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (m *Media) AfterFind(tx *gorm.DB) error {
+	m.setURLs()
+	return nil
+}
+
+func (m *Media) AfterSave(tx *gorm.DB) error {
+	m.setURLs()
+	return nil
+}
+
+func (m *Media) setURLs() {
+	m.URL = MediaURL(m.Filename)
+	var srcset []string
+	for i := range m.Variants {
+		m.Variants[i].URL = MediaURL(m.Variants[i].Filename)
+		srcset = append(srcset, fmt.Sprintf("%s %dw", m.Variants[i].URL, m.Variants[i].Width))
+	}
+	if m.Width > 0 {
+		srcset = append(srcset, fmt.Sprintf("%s %dw", m.URL, m.Width))
+	}
+	m.SrcSet = strings.Join(srcset, ", ")
+}
+
+// MediaURL is the path a stored file is served from
+func MediaURL(filename string) string {
+	return "/uploads/" + filename
+}
+
+// VariantPrefix starts the name of every resized copy of a stored file
+func VariantPrefix(filename string) string {
+	return strings.TrimSuffix(filename, filepath.Ext(filename)) + "_w"
+}
+
+// WithImages preloads a blog's images, in order, with their media. Media
+// deleted from the library still shows on the blogs that use it.
+func WithImages(db *gorm.DB) *gorm.DB {
+	return db.Preload("Images", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).Preload("Images.Media", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	})
+}
+
+// MediaRefCountSQL counts the blogs, trashed ones included, that show a
+// media row in their images or link to it from their content
+const MediaRefCountSQL = "(SELECT COUNT(*) FROM blogs WHERE" +
+	" EXISTS (SELECT 1 FROM blog_images WHERE blog_images.blog_id = blogs.id AND blog_images.media_id = media.id)" +
+	" OR blogs.linked_media LIKE '%\"' || media.id || '\"%')"
+
+// RecountMediaRefs sets the reference count of the given media from the
+// blogs that use it. Counting rather than adding and subtracting keeps the
+// numbers right whatever path changed them.
+func RecountMediaRefs(tx *gorm.DB, mediaIDs []string) error {
+	if len(mediaIDs) == 0 {
+		return nil
+	}
+	return tx.Exec("UPDATE media SET ref_count = "+MediaRefCountSQL+" WHERE id IN ?", mediaIDs).Error
+}
+
+// uploadLink matches a link to a stored file in HTML or Markdown
+var uploadLink = regexp.MustCompile(`/uploads/([^\s"'<>()?#,\\]+)`)
+
+// variantName matches a resized copy, named after its original's stem
+var variantName = regexp.MustCompile(`^(.+)_w[0-9]+\.(jpg|png)$`)
+
+// variantSourceExts are the extensions of originals that get resized copies
+var variantSourceExts = []string{".jpg", ".jpeg", ".png", ".webp"}
+
+// ContentMediaIDs returns the IDs of the media that texts link to under
+// /uploads/, directly or through a resized copy, sorted. Media deleted from
+// the library counts, as its file is still served.
+func ContentMediaIDs(tx *gorm.DB, texts ...string) ([]string, error) {
+	names := map[string]bool{}
+	for _, text := range texts {
+		for _, m := range uploadLink.FindAllStringSubmatch(text, -1) {
+			name := m[1]
+			if unescaped, err := url.PathUnescape(name); err == nil {
+				name = unescaped
+			}
+			names[name] = true
+			if v := variantName.FindStringSubmatch(name); v != nil {
+				for _, ext := range variantSourceExts {
+					names[v[1]+ext] = true
+					names[v[1]+strings.ToUpper(ext)] = true
+				}
+			}
+		}
+	}
+	ids := []string{}
+	if len(names) == 0 {
+		return ids, nil
+	}
+	filenames := make([]string, 0, len(names))
+	for name := range names {
+		filenames = append(filenames, name)
+	}
+	if err := tx.Unscoped().Model(&Media{}).Where("filename IN ?", filenames).Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// LinkContentMedia records the media the blog's content links to. It
+// returns the media linked before or after, whose reference counts need
+// recounting once the blog is saved.
+func (b *Blog) LinkContentMedia(tx *gorm.DB) ([]string, error) {
+	ids, err := ContentMediaIDs(tx, b.Content, b.ContentHTML)
+	if err != nil {
+		return nil, err
+	}
+	changed := append(append([]string{}, b.LinkedMedia...), ids...)
+	b.LinkedMedia = ids
+	return changed, nil
+}
+
+// BlogMediaIDs returns the IDs of a blog's images in order
+func BlogMediaIDs(tx *gorm.DB, blogID string) ([]string, error) {
+	ids := []string{}
+	err := tx.Model(&BlogImage{}).Where("blog_id = ?", blogID).Order("position ASC").Pluck("media_id", &ids).Error
+	return ids, err
+}
+
+// SetBlogImages replaces a blog's images with the given media, in order,
+// and updates the media's reference counts. Media whose files are gone is
+// left out.
+func SetBlogImages(tx *gorm.DB, blogID string, mediaIDs []string) error {
+	previous, err := BlogMediaIDs(tx, blogID)
+	if err != nil {
+		return err
+	}
+	if err := tx.Where("blog_id = ?", blogID).Delete(&BlogImage{}).Error; err != nil {
+		return err
+	}
+	if err := insertBlogImages(tx, blogID, mediaIDs); err != nil {
+		return err
+	}
+	return RecountMediaRefs(tx, append(previous, mediaIDs...))
+}
+
+func insertBlogImages(tx *gorm.DB, blogID string, mediaIDs []string) error {
+	if len(mediaIDs) == 0 {
+		return nil
+	}
+	// Media deleted from the library can stay on a blog while it is in use
+	var existing []string
+	if err := tx.Unscoped().Model(&Media{}).Where("id IN ?", mediaIDs).Pluck("id", &existing).Error; err != nil {
+		return err
+	}
+	found := map[string]bool{}
+	for _, id := range existing {
+		found[id] = true
+	}
+	var images []BlogImage
+	for _, id := range mediaIDs {
+		if found[id] {
+			images = append(images, BlogImage{BlogID: blogID, MediaID: id, Position: len(images)})
+			found[id] = false // Each media item appears once
+		}
+	}
+	if len(images) == 0 {
+		return nil
+	}
+	return tx.Create(&images).Error
+}
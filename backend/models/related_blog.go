@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// RelatedBlog is a precomputed "read next" suggestion, rebuilt in the
+// background by jobs.RecomputeRelated
+type RelatedBlog struct {
+	BlogID    string    `json:"blog_id" gorm:"primaryKey"`
+	RelatedID string    `json:"related_id" gorm:"primaryKey"`
+	Score     float64   `json:"score"`
+	Rank      int       `json:"rank"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,70 @@
+package models
+
+// Roles a user can hold. A user may hold several.
+const (
+	RoleAdmin     = "admin"
+	RoleEditor    = "editor"
+	RoleAuthor    = "author"
+	RoleModerator = "moderator"
+	RoleReader    = "reader"
+)
+
+// Permissions checked by middleware.RequirePermission
+const (
+	PermAccessAdmin      = "access_admin"      // reach /api/admin at all
+	PermWritePosts       = "write_posts"       // create posts and edit their own
+	PermEditOthersPosts  = "edit_others_posts" // edit posts by other authors
+	PermPublish          = "publish"           // publish, unpublish and schedule
+	PermDeletePosts      = "delete_posts"
+	PermManageTaxonomy   = "manage_taxonomy" // tags and categories
+	PermModerateComments = "moderate_comments"
+	PermViewAnalytics    = "view_analytics" // likers and viewers
+	PermUploadMedia      = "upload_media"
+	PermManageUsers      = "manage_users" // assign roles
+)
+
+var RolePermissions = map[string][]string{
+	RoleAdmin: {
+		PermAccessAdmin, PermWritePosts, PermEditOthersPosts, PermPublish, PermDeletePosts,
+		PermManageTaxonomy, PermModerateComments, PermViewAnalytics, PermUploadMedia, PermManageUsers,
+	},
+	RoleEditor: {
+		PermAccessAdmin, PermWritePosts, PermEditOthersPosts, PermPublish, PermDeletePosts,
+		PermManageTaxonomy, PermModerateComments, PermViewAnalytics, PermUploadMedia,
+	},
+	RoleAuthor:    {PermAccessAdmin, PermWritePosts, PermUploadMedia},
+	RoleModerator: {PermAccessAdmin, PermModerateComments},
+	RoleReader:    {},
+}
+
+func IsValidRole(role string) bool {
+	_, ok := RolePermissions[role]
+	return ok
+}
+
+// HasPermission reports whether any of the roles grants perm
+func HasPermission(roles []string, perm string) bool {
+	for _, role := range roles {
+		for _, p := range RolePermissions[role] {
+			if p == perm {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PermissionsFor lists the distinct permissions granted by roles
+func PermissionsFor(roles []string) []string {
+	seen := map[string]bool{}
+	perms := []string{}
+	for _, role := range roles {
+		for _, p := range RolePermissions[role] {
+			if !seen[p] {
+				seen[p] = true
+				perms = append(perms, p)
+			}
+		}
+	}
+	return perms
+}
@@ -8,12 +8,16 @@ import (
 )
 
 type User struct {
-	ID        string    `json:"id" gorm:"primaryKey"`
-	Username  string    `json:"username" gorm:"unique;not null"`
-	Password  string    `json:"-" gorm:"not null"`
-	IsAdmin   bool      `json:"is_admin" gorm:"default:false"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID          string    `json:"id" gorm:"primaryKey"`
+	Username    string    `json:"username" gorm:"unique;not null"`
+	Password    string    `json:"-" gorm:"not null"`
+	IsAdmin     bool      `json:"is_admin" gorm:"default:false"`          // Kept in sync with the admin role
+	Roles       []string  `json:"roles" gorm:"serializer:json;type:text"` // See models/role.go
+	DisplayName string    `json:"display_name"`
+	Bio         string    `json:"bio" gorm:"type:text"`
+	AvatarURL   string    `json:"avatar_url"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
 }
 
 func (u *User) BeforeCreate(tx *gorm.DB) error {
@@ -22,3 +26,15 @@ func (u *User) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// EffectiveRoles returns the user's roles. Accounts created before roles
+// existed fall back to admin or reader based on IsAdmin.
+func (u *User) EffectiveRoles() []string {
+	if len(u.Roles) > 0 {
+		return u.Roles
+	}
+	if u.IsAdmin {
+		return []string{RoleAdmin}
+	}
+	return []string{RoleReader}
+}
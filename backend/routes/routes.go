@@ -3,6 +3,7 @@ package routes
 import (
 	"kunals-blog-backend/controllers"
 	"kunals-blog-backend/middleware"
+	"kunals-blog-backend/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -20,7 +21,19 @@ func SetupRoutes(router *gin.Engine) {
 		{
 			// Blog routes (read-only for public)
 			public.GET("/blogs", controllers.GetBlogs)
+			public.GET("/blogs/featured", controllers.GetFeaturedBlogs)
 			public.GET("/blogs/:id", controllers.GetBlog)
+			public.GET("/blogs/slug/:slug", controllers.GetBlogBySlug)
+			public.GET("/blogs/:id/related", controllers.GetRelatedBlogs)
+			public.GET("/search", controllers.SearchBlogs)
+
+			// Authors
+			public.GET("/authors", controllers.ListAuthors)
+			public.GET("/authors/:username", controllers.GetAuthor)
+
+			// Taxonomy
+			public.GET("/tags", controllers.ListTags)
+			public.GET("/categories", controllers.ListCategories)
 
 			// Comment routes
 			public.GET("/blogs/:id/comments", controllers.GetComments)
@@ -38,20 +51,59 @@ func SetupRoutes(router *gin.Engine) {
 			auth.POST("/login", controllers.Login)
 			auth.POST("/signup", controllers.Signup)
 			auth.GET("/validate", middleware.AuthMiddleware(), controllers.ValidateToken)
+			auth.PUT("/profile", middleware.AuthMiddleware(), controllers.UpdateProfile)
 		}
 
-		// Admin routes (protected)
+		// Admin routes (protected). Everyone here needs access_admin; each
+		// route then checks the permission it needs.
 		admin := api.Group("/admin")
 		admin.Use(middleware.AuthMiddleware())
-		admin.Use(middleware.AdminMiddleware())
+		admin.Use(middleware.RequirePermission(models.PermAccessAdmin))
 		{
+			write := middleware.RequirePermission(models.PermWritePosts)
+			publish := middleware.RequirePermission(models.PermPublish)
+			taxonomy := middleware.RequirePermission(models.PermManageTaxonomy)
+
 			// Blog management
-			admin.POST("/blogs", controllers.CreateBlog)
-			admin.PUT("/blogs/:id", controllers.UpdateBlog)
-			admin.POST("/blogs/:id/versions/:versionId/apply", controllers.ApplyVersion)
-			admin.DELETE("/blogs/:id", controllers.DeleteBlog)
-			admin.POST("/blogs/:id/publish", controllers.PublishBlog)
-			admin.POST("/blogs/:id/unpublish", controllers.UnpublishBlog)
+			admin.POST("/blogs", write, controllers.CreateBlog)
+			admin.GET("/blogs/:id", write, controllers.GetAdminBlog)
+			admin.PUT("/blogs/:id", write, controllers.UpdateBlog)
+			admin.DELETE("/blogs/:id", middleware.RequirePermission(models.PermDeletePosts), controllers.DeleteBlog)
+			admin.POST("/blogs/:id/publish", publish, controllers.PublishBlog)
+			admin.POST("/blogs/:id/unpublish", publish, controllers.UnpublishBlog)
+			admin.POST("/render", write, controllers.RenderContent)
+
+			// Versions
+			admin.GET("/blogs/:id/versions", write, controllers.ListVersions)
+			admin.GET("/blogs/:id/versions/:versionId", write, controllers.GetVersion)
+			admin.POST("/blogs/:id/versions/merge", write, controllers.MergeVersions)
+			admin.POST("/blogs/:id/versions/:versionId/apply", write, controllers.ApplyVersion)
+			admin.POST("/blogs/:id/versions/:versionId/reject", write, controllers.RejectVersion)
+			admin.POST("/blogs/:id/versions/:versionId/restore", write, controllers.RestoreVersion)
+			admin.DELETE("/blogs/:id/versions/:versionId", write, controllers.DiscardVersion)
+			admin.GET("/blogs/:id/diff", write, controllers.DiffVersions)
+			admin.GET("/blogs/:id/autosave", write, controllers.GetAutosave)
+			admin.PUT("/blogs/:id/autosave", write, controllers.Autosave)
+			admin.DELETE("/blogs/:id/autosave", write, controllers.DiscardAutosave)
+			admin.GET("/versions/prune-preview", middleware.RequirePermission(models.PermEditOthersPosts), controllers.PreviewVersionPrune)
+
+			// Trash
+			trash := middleware.RequirePermission(models.PermDeletePosts)
+			admin.GET("/trash", trash, controllers.ListTrash)
+			admin.POST("/trash/:id/restore", trash, controllers.RestoreBlog)
+			admin.DELETE("/trash/:id", trash, controllers.PurgeBlog)
+
+			// Pinned and featured posts
+			admin.GET("/highlights", publish, controllers.ListHighlightedBlogs)
+			admin.PUT("/blogs/:id/pin", publish, controllers.PinBlog)
+			admin.DELETE("/blogs/:id/pin", publish, controllers.UnpinBlog)
+			admin.PUT("/blogs/:id/feature", publish, controllers.FeatureBlog)
+			admin.DELETE("/blogs/:id/feature", publish, controllers.UnfeatureBlog)
+
+			// Scheduled publishing
+			admin.GET("/scheduled", publish, controllers.ListScheduledBlogs)
+			admin.PUT("/blogs/:id/schedule", publish, controllers.ScheduleBlog)
+			admin.DELETE("/blogs/:id/schedule", publish, controllers.CancelSchedule)
 
 			// Get all blogs including drafts
 			admin.GET("/blogs", func(c *gin.Context) {
@@ -59,12 +111,43 @@ func SetupRoutes(router *gin.Engine) {
 				controllers.GetBlogs(c)
 			})
 
+			// Taxonomy management (counts include drafts)
+			admin.GET("/tags", func(c *gin.Context) {
+				c.Request.URL.RawQuery += "&published_only=false"
+				controllers.ListTags(c)
+			})
+			admin.POST("/tags", taxonomy, controllers.CreateTag)
+			admin.PUT("/tags/:id", taxonomy, controllers.UpdateTag)
+			admin.DELETE("/tags/:id", taxonomy, controllers.DeleteTag)
+			admin.GET("/categories", func(c *gin.Context) {
+				c.Request.URL.RawQuery += "&published_only=false"
+				controllers.ListCategories(c)
+			})
+			admin.POST("/categories", taxonomy, controllers.CreateCategory)
+			admin.PUT("/categories/:id", taxonomy, controllers.UpdateCategory)
+			admin.DELETE("/categories/:id", taxonomy, controllers.DeleteCategory)
+
+			// Comment moderation
+			admin.DELETE("/blogs/:id/comments/:commentId", middleware.RequirePermission(models.PermModerateComments), controllers.DeleteComment)
+
 			// Admin insights
-			admin.GET("/blogs/:id/likers", controllers.AdminListLikers)
-			admin.GET("/blogs/:id/viewers", controllers.AdminListViewers)
+			analytics := middleware.RequirePermission(models.PermViewAnalytics)
+			admin.GET("/blogs/:id/likers", analytics, controllers.AdminListLikers)
+			admin.GET("/blogs/:id/viewers", analytics, controllers.AdminListViewers)
+
+			// Image upload and the media library
+			media := middleware.RequirePermission(models.PermUploadMedia)
+			admin.POST("/upload/image", media, controllers.UploadImage)
+			admin.GET("/media", media, controllers.ListMedia)
+			admin.GET("/media/:id", media, controllers.GetMedia)
+			admin.PUT("/media/:id", media, controllers.UpdateMedia)
+			admin.DELETE("/media/:id", media, controllers.DeleteMedia)
 
-			// Image upload
-			admin.POST("/upload/image", controllers.UploadImage)
+			// Users and roles
+			users := middleware.RequirePermission(models.PermManageUsers)
+			admin.GET("/roles", users, controllers.ListRoles)
+			admin.GET("/users", users, controllers.ListUsers)
+			admin.PUT("/users/:id/roles", users, controllers.SetUserRoles)
 		}
 	}
 
@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+
+	"kunals-blog-backend/config"
+)
+
+// Storage keeps uploaded files. Keys are plain file names such as
+// "1700000000_ab12cd34.jpg"; resized copies sit next to their original.
+type Storage interface {
+	// Name is the backend's name as used in STORAGE_BACKEND
+	Name() string
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	// Delete removes a file; removing one that doesn't exist is not an error
+	Delete(ctx context.Context, key string) error
+	List(ctx context.Context, prefix string) ([]string, error)
+	// URL is where clients can fetch the file straight from the backend,
+	// or "" when the app has to serve it
+	URL(ctx context.Context, key string) (string, error)
+}
+
+var (
+	ErrNotFound   = errors.New("file not found")
+	ErrInvalidKey = errors.New("invalid file name")
+)
+
+var current Storage
+
+// Init opens the backend chosen by STORAGE_BACKEND. Call it before anything
+// reads or writes uploads.
+func Init() error {
+	s, err := Open(config.GetConfig().StorageBackend)
+	if err != nil {
+		return err
+	}
+	current = s
+	log.Printf("Storing uploads in %s storage", s.Name())
+	return nil
+}
+
+// Default returns the backend opened by Init, or local storage if Init was
+// never called
+func Default() Storage {
+	if current == nil {
+		return NewLocal(config.GetConfig().UploadPath)
+	}
+	return current
+}
+
+// Open creates a backend by name: "local" or "s3"
+func Open(name string) (Storage, error) {
+	cfg := config.GetConfig()
+	switch name {
+	case "", "local":
+		return NewLocal(cfg.UploadPath), nil
+	case "s3":
+		return NewS3(S3Options{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			UseSSL:    cfg.S3UseSSL,
+			PublicURL: cfg.S3PublicURL,
+			URLExpiry: cfg.S3URLExpiry,
+		})
+	}
+	return nil, fmt.Errorf("unknown storage backend %q (available: local, s3)", name)
+}
@@ -0,0 +1,375 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type DiffOp string
+
+const (
+	DiffEqual  DiffOp = "equal"
+	DiffInsert DiffOp = "insert"
+	DiffDelete DiffOp = "delete"
+)
+
+// DiffEdit is a run of tokens that were kept, inserted or deleted
+type DiffEdit struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
+
+// DiffHunk is a group of nearby changes with some unchanged context around
+// them. Positions are 1-based and count tokens (lines or words).
+type DiffHunk struct {
+	OldStart int        `json:"old_start"`
+	OldCount int        `json:"old_count"`
+	NewStart int        `json:"new_start"`
+	NewCount int        `json:"new_count"`
+	Edits    []DiffEdit `json:"edits"`
+}
+
+// wordTokens keeps HTML tags whole and separates words from the whitespace
+// between them, so joining the tokens gives back the original text
+var wordTokens = regexp.MustCompile(`<[^>]*>|\s+|[^\s<]+|<`)
+
+// SplitLines splits text into lines, each keeping its trailing newline
+func SplitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// SplitWords splits text into words, whitespace runs and HTML tags
+func SplitWords(text string) []string {
+	return wordTokens.FindAllString(text, -1)
+}
+
+// Diff returns the shortest edit script turning a into b, one edit per
+// token. It uses Myers' algorithm in linear space.
+func Diff(a, b []string) []DiffEdit {
+	// Compare small integers instead of strings
+	ids := map[string]int{}
+	intern := func(tokens []string) []int {
+		out := make([]int, len(tokens))
+		for i, t := range tokens {
+			id, ok := ids[t]
+			if !ok {
+				id = len(ids)
+				ids[t] = id
+			}
+			out[i] = id
+		}
+		return out
+	}
+	m := myers{a: intern(a), b: intern(b)}
+
+	edits := make([]DiffEdit, 0, len(a)+len(b))
+	path := m.path(0, 0, len(a), len(b))
+	for i := 1; i < len(path); i++ {
+		x1, y1 := path[i-1][0], path[i-1][1]
+		x2, y2 := path[i][0], path[i][1]
+		for x1 < x2 && y1 < y2 && m.a[x1] == m.b[y1] {
+			edits = append(edits, DiffEdit{Op: DiffEqual, Text: a[x1]})
+			x1, y1 = x1+1, y1+1
+		}
+		if x2-x1 < y2-y1 {
+			edits = append(edits, DiffEdit{Op: DiffInsert, Text: b[y1]})
+			y1++
+		} else if x2-x1 > y2-y1 {
+			edits = append(edits, DiffEdit{Op: DiffDelete, Text: a[x1]})
+			x1++
+		}
+		for x1 < x2 && y1 < y2 && m.a[x1] == m.b[y1] {
+			edits = append(edits, DiffEdit{Op: DiffEqual, Text: a[x1]})
+			x1, y1 = x1+1, y1+1
+		}
+	}
+	return edits
+}
+
+type myers struct {
+	a, b []int
+}
+
+// path returns the points the edit path passes through inside the box
+// (left, top) to (right, bottom), splitting it at the middle snake
+func (m myers) path(left, top, right, bottom int) [][2]int {
+	start, finish, ok := m.midpoint(left, top, right, bottom)
+	if !ok {
+		return nil
+	}
+	head := m.path(left, top, start[0], start[1])
+	if head == nil {
+		head = [][2]int{start}
+	}
+	tail := m.path(finish[0], finish[1], right, bottom)
+	if tail == nil {
+		tail = [][2]int{finish}
+	}
+	return append(head, tail...)
+}
+
+func (m myers) midpoint(left, top, right, bottom int) (start, finish [2]int, ok bool) {
+	width, height := right-left, bottom-top
+	size := width + height
+	if size == 0 {
+		return start, finish, false
+	}
+	delta := width - height
+	max := (size + 1) / 2
+	vf := make([]int, 2*max+1)
+	vb := make([]int, 2*max+1)
+	vf[max+1] = left
+	vb[max+1] = bottom
+
+	for d := 0; d <= max; d++ {
+		// Forward from the top left
+		for k := d; k >= -d; k -= 2 {
+			c := k - delta
+			var x, px int
+			if k == -d || (k != d && vf[max+k-1] < vf[max+k+1]) {
+				px = vf[max+k+1]
+				x = px
+			} else {
+				px = vf[max+k-1]
+				x = px + 1
+			}
+			y := top + (x - left) - k
+			py := y
+			if d != 0 && x == px {
+				py = y - 1
+			}
+			for x < right && y < bottom && m.a[x] == m.b[y] {
+				x, y = x+1, y+1
+			}
+			vf[max+k] = x
+			if delta%2 != 0 && c >= -(d-1) && c <= d-1 && y >= vb[max+c] {
+				return [2]int{px, py}, [2]int{x, y}, true
+			}
+		}
+		// Backward from the bottom right
+		for c := d; c >= -d; c -= 2 {
+			k := c + delta
+			var y, py int
+			if c == -d || (c != d && vb[max+c-1] > vb[max+c+1]) {
+				py = vb[max+c+1]
+				y = py
+			} else {
+				py = vb[max+c-1]
+				y = py - 1
+			}
+			x := left + (y - top) + k
+			px := x
+			if d != 0 && y == py {
+				px = x + 1
+			}
+			for x > left && y > top && m.a[x-1] == m.b[y-1] {
+				x, y = x-1, y-1
+			}
+			vb[max+c] = y
+			if delta%2 == 0 && k >= -d && k <= d && x <= vf[max+k] {
+				return [2]int{x, y}, [2]int{px, py}, true
+			}
+		}
+	}
+	return start, finish, false
+}
+
+// DiffHunks groups a per-token edit script into hunks with up to context
+// unchanged tokens on each side. Adjacent edits of the same kind are joined.
+func DiffHunks(edits []DiffEdit, context int) []DiffHunk {
+	var hunks []DiffHunk
+	oldPos, newPos := 0, 0
+	for i := 0; i < len(edits); {
+		if edits[i].Op == DiffEqual {
+			oldPos, newPos = oldPos+1, newPos+1
+			i++
+			continue
+		}
+
+		// Back up over leading context
+		start := i
+		for start > 0 && i-start < context && edits[start-1].Op == DiffEqual {
+			start--
+		}
+		hunk := DiffHunk{OldStart: oldPos - (i - start) + 1, NewStart: newPos - (i - start) + 1}
+
+		// Extend while the next change is close enough to share context
+		end := i
+		for end < len(edits) {
+			if edits[end].Op != DiffEqual {
+				end++
+				continue
+			}
+			run := end
+			for run < len(edits) && edits[run].Op == DiffEqual {
+				run++
+			}
+			if run == len(edits) || run-end > 2*context {
+				trailing := context
+				if run-end < trailing {
+					trailing = run - end
+				}
+				end += trailing
+				break
+			}
+			end = run
+		}
+
+		for _, e := range edits[start:end] {
+			if e.Op != DiffInsert {
+				hunk.OldCount++
+			}
+			if e.Op != DiffDelete {
+				hunk.NewCount++
+			}
+			if n := len(hunk.Edits); n > 0 && hunk.Edits[n-1].Op == e.Op {
+				hunk.Edits[n-1].Text += e.Text
+			} else {
+				hunk.Edits = append(hunk.Edits, e)
+			}
+		}
+		hunks = append(hunks, hunk)
+
+		for _, e := range edits[i:end] {
+			if e.Op != DiffInsert {
+				oldPos++
+			}
+			if e.Op != DiffDelete {
+				newPos++
+			}
+		}
+		i = end
+	}
+	return hunks
+}
+
+// DiffStats counts inserted and deleted tokens
+func DiffStats(edits []DiffEdit) (insertions, deletions int) {
+	for _, e := range edits {
+		switch e.Op {
+		case DiffInsert:
+			insertions++
+		case DiffDelete:
+			deletions++
+		}
+	}
+	return insertions, deletions
+}
+
+// UnifiedDiff renders hunks as text. Line hunks use the usual unified
+// format; word hunks mark changes inline as [-deleted-]{+inserted+}.
+func UnifiedDiff(fromLabel, toLabel string, hunks []DiffHunk, byLine bool) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromLabel, toLabel)
+	for _, h := range hunks {
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", h.OldStart, h.OldCount, h.NewStart, h.NewCount)
+		if !byLine {
+			for _, e := range h.Edits {
+				switch e.Op {
+				case DiffEqual:
+					sb.WriteString(e.Text)
+				case DiffDelete:
+					sb.WriteString("[-" + e.Text + "-]")
+				case DiffInsert:
+					sb.WriteString("{+" + e.Text + "+}")
+				}
+			}
+			sb.WriteString("\n")
+			continue
+		}
+		prefix := map[DiffOp]string{DiffEqual: " ", DiffDelete: "-", DiffInsert: "+"}
+		for _, e := range h.Edits {
+			for _, line := range SplitLines(e.Text) {
+				sb.WriteString(prefix[e.Op] + strings.TrimSuffix(line, "\n") + "\n")
+			}
+		}
+	}
+	return sb.String()
+}
+
+// matches maps each token of a to the index of the equal token in b, or -1
+// where the token was deleted
+func matches(edits []DiffEdit, n int) []int {
+	m := make([]int, n)
+	i, j := 0, 0
+	for _, e := range edits {
+		switch e.Op {
+		case DiffEqual:
+			m[i] = j
+			i, j = i+1, j+1
+		case DiffDelete:
+			m[i] = -1
+			i++
+		case DiffInsert:
+			j++
+		}
+	}
+	return m
+}
+
+// Merge3 combines two edits of base made independently (diff3). Changes
+// only one side made are taken as they are; chunks both sides changed
+// differently are kept with conflict markers and counted.
+func Merge3(base, ours, theirs []string, oursLabel, theirsLabel string) (merged string, conflicts int) {
+	toOurs := matches(Diff(base, ours), len(base))
+	toTheirs := matches(Diff(base, theirs), len(base))
+
+	var sb strings.Builder
+	i, j, k := 0, 0, 0
+	for {
+		// Stable run: the same tokens line up in all three
+		n := 0
+		for i+n < len(base) && toOurs[i+n] == j+n && toTheirs[i+n] == k+n {
+			n++
+		}
+		if n > 0 {
+			sb.WriteString(strings.Join(base[i:i+n], ""))
+			i, j, k = i+n, j+n, k+n
+			continue
+		}
+
+		// Unstable chunk up to the next base token both sides kept
+		l := i
+		for l < len(base) && (toOurs[l] < 0 || toTheirs[l] < 0) {
+			l++
+		}
+		oEnd, tEnd := len(ours), len(theirs)
+		if l < len(base) {
+			oEnd, tEnd = toOurs[l], toTheirs[l]
+		}
+		b := strings.Join(base[i:l], "")
+		o := strings.Join(ours[j:oEnd], "")
+		t := strings.Join(theirs[k:tEnd], "")
+		switch {
+		case o == t, t == b:
+			sb.WriteString(o)
+		case o == b:
+			sb.WriteString(t)
+		default:
+			conflicts++
+			sb.WriteString(conflictMarkers(o, t, oursLabel, theirsLabel))
+		}
+		i, j, k = l, oEnd, tEnd
+		if i == len(base) && j == len(ours) && k == len(theirs) {
+			break
+		}
+	}
+	return sb.String(), conflicts
+}
+
+func conflictMarkers(ours, theirs, oursLabel, theirsLabel string) string {
+	withNewline := func(s string) string {
+		if s != "" && !strings.HasSuffix(s, "\n") {
+			return s + "\n"
+		}
+		return s
+	}
+	return "<<<<<<< " + oursLabel + "\n" + withNewline(ours) +
+		"=======\n" + withNewline(theirs) +
+		">>>>>>> " + theirsLabel + "\n"
+}
@@ -0,0 +1,319 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// sides rebuilds both inputs from an edit script
+func sides(edits []DiffEdit) (a, b string) {
+	var sa, sb strings.Builder
+	for _, e := range edits {
+		if e.Op != DiffInsert {
+			sa.WriteString(e.Text)
+		}
+		if e.Op != DiffDelete {
+			sb.WriteString(e.Text)
+		}
+	}
+	return sa.String(), sb.String()
+}
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"empty", "", []string{}},
+		{"trailing newline", "a\nb\n", []string{"a\n", "b\n"}},
+		{"no trailing newline", "a\nb", []string{"a\n", "b"}},
+		{"blank lines", "\n\n", []string{"\n", "\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitLines(tt.text)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitLines(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitWords(t *testing.T) {
+	text := "hello <b>big</b>  world"
+	want := []string{"hello", " ", "<b>", "big", "</b>", "  ", "world"}
+	got := SplitWords(text)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("SplitWords(%q) = %q, want %q", text, got, want)
+	}
+	if joined := strings.Join(got, ""); joined != text {
+		t.Errorf("tokens join to %q, want %q", joined, text)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name                  string
+		a, b                  string
+		insertions, deletions int
+	}{
+		{"both empty", "", "", 0, 0},
+		{"from empty", "", "a\nb\n", 2, 0},
+		{"to empty", "a\nb\n", "", 0, 2},
+		{"identical", "a\nb\nc\n", "a\nb\nc\n", 0, 0},
+		{"replace middle", "a\nb\nc\n", "a\nx\nc\n", 1, 1},
+		{"insert at start", "b\nc\n", "a\nb\nc\n", 1, 0},
+		{"delete at end", "a\nb\nc\n", "a\nb\n", 0, 1},
+		{"newline added at end", "a\nb", "a\nb\n", 1, 1},
+		{"newline removed at end", "a\nb\n", "a\nb", 1, 1},
+		{"all different", "a\nb\n", "c\nd\n", 2, 2},
+		{"moved line", "a\nb\nc\n", "b\nc\na\n", 1, 1},
+		{"repeated lines", "a\na\nb\na\n", "a\nb\na\na\n", 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edits := Diff(SplitLines(tt.a), SplitLines(tt.b))
+			if a, b := sides(edits); a != tt.a || b != tt.b {
+				t.Errorf("edits rebuild %q -> %q, want %q -> %q", a, b, tt.a, tt.b)
+			}
+			ins, del := DiffStats(edits)
+			if ins != tt.insertions || del != tt.deletions {
+				t.Errorf("DiffStats = +%d -%d, want +%d -%d", ins, del, tt.insertions, tt.deletions)
+			}
+		})
+	}
+}
+
+func TestDiffExactScript(t *testing.T) {
+	got := Diff([]string{"a\n", "b\n", "c\n"}, []string{"a\n", "x\n", "c\n"})
+	want := []DiffEdit{
+		{DiffEqual, "a\n"},
+		{DiffDelete, "b\n"},
+		{DiffInsert, "x\n"},
+		{DiffEqual, "c\n"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff = %v, want %v", got, want)
+	}
+}
+
+// Long inputs go through the midpoint recursion several levels deep
+func TestDiffLong(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 500; i++ {
+		line := string(rune('a'+i%26)) + "\n"
+		a = append(a, line)
+		if i%7 != 0 {
+			b = append(b, line)
+		}
+		if i%11 == 0 {
+			b = append(b, "new\n")
+		}
+	}
+	edits := Diff(a, b)
+	if ga, gb := sides(edits); ga != strings.Join(a, "") || gb != strings.Join(b, "") {
+		t.Fatal("edits don't rebuild the inputs")
+	}
+	ins, del := DiffStats(edits)
+	if ins+del > 72+46 {
+		t.Errorf("edit script has %d edits, more than the %d made", ins+del, 72+46)
+	}
+}
+
+func TestDiffHunks(t *testing.T) {
+	lines := func(s string) []string { return SplitLines(strings.ReplaceAll(s, " ", "\n")) }
+	tests := []struct {
+		name    string
+		a, b    string
+		context int
+		want    [][4]int // old start, old count, new start, new count
+	}{
+		{"no changes", "1 2 3 ", "1 2 3 ", 3, nil},
+		{"both empty", "", "", 3, nil},
+		{"single change", "1 2 3 4 5 6 7 8 9 ", "1 2 3 4 X 6 7 8 9 ", 1, [][4]int{{4, 3, 4, 3}}},
+		{"context clipped at start", "1 2 3 ", "X 2 3 ", 3, [][4]int{{1, 3, 1, 3}}},
+		{"close changes share a hunk", "1 2 3 4 5 6 7 8 9 ", "1 X 3 4 X 6 7 8 9 ", 1, [][4]int{{1, 6, 1, 6}}},
+		{"far changes split", "1 2 3 4 5 6 7 8 9 ", "1 X 3 4 5 6 7 X 9 ", 1, [][4]int{{1, 3, 1, 3}, {7, 3, 7, 3}}},
+		{"zero context", "1 2 3 ", "1 X 3 ", 0, [][4]int{{2, 1, 2, 1}}},
+		{"insert only", "1 2 ", "1 X 2 ", 1, [][4]int{{1, 2, 1, 3}}},
+		{"from empty", "", "1 2 ", 3, [][4]int{{1, 0, 1, 2}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hunks := DiffHunks(Diff(lines(tt.a), lines(tt.b)), tt.context)
+			var got [][4]int
+			for _, h := range hunks {
+				got = append(got, [4]int{h.OldStart, h.OldCount, h.NewStart, h.NewCount})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("hunks = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffHunksJoinsEdits(t *testing.T) {
+	hunks := DiffHunks(Diff(SplitLines("a\nb\nc\nd\n"), SplitLines("a\nx\ny\nd\n")), 1)
+	if len(hunks) != 1 {
+		t.Fatalf("got %d hunks, want 1", len(hunks))
+	}
+	want := []DiffEdit{
+		{DiffEqual, "a\n"},
+		{DiffDelete, "b\nc\n"},
+		{DiffInsert, "x\ny\n"},
+		{DiffEqual, "d\n"},
+	}
+	if !reflect.DeepEqual(hunks[0].Edits, want) {
+		t.Errorf("edits = %v, want %v", hunks[0].Edits, want)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		a, b   string
+		byLine bool
+		want   string
+	}{
+		{
+			name: "no changes", a: "a\n", b: "a\n", byLine: true,
+			want: "--- from\n+++ to\n",
+		},
+		{
+			name: "lines", a: "a\nb\nc\n", b: "a\nx\nc\n", byLine: true,
+			want: "--- from\n+++ to\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n",
+		},
+		{
+			name: "missing trailing newline", a: "a\nb", b: "a\nb\n", byLine: true,
+			want: "--- from\n+++ to\n@@ -1,2 +1,2 @@\n a\n-b\n+b\n",
+		},
+		{
+			name: "from empty", a: "", b: "a\nb\n", byLine: true,
+			want: "--- from\n+++ to\n@@ -1,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name: "words", a: "hello <b>big</b> world", b: "hello <b>small</b> world",
+			want: "--- from\n+++ to\n@@ -1,7 +1,7 @@\nhello <b>[-big-]{+small+}</b> world\n",
+		},
+		{
+			name: "word insert", a: "one two", b: "one new two",
+			want: "--- from\n+++ to\n@@ -1,3 +1,5 @@\none{+ new+} two\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			split := SplitWords
+			if tt.byLine {
+				split = SplitLines
+			}
+			hunks := DiffHunks(Diff(split(tt.a), split(tt.b)), 3)
+			if got := UnifiedDiff("from", "to", hunks, tt.byLine); got != tt.want {
+				t.Errorf("UnifiedDiff =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMerge3(t *testing.T) {
+	tests := []struct {
+		name               string
+		base, ours, theirs string
+		byWord             bool
+		want               string
+		conflicts          int
+	}{
+		{
+			name: "all empty",
+			want: "",
+		},
+		{
+			name: "only theirs changed",
+			base: "a\nb\nc\n", ours: "a\nb\nc\n", theirs: "a\nB\nc\n",
+			want: "a\nB\nc\n",
+		},
+		{
+			name: "only ours changed",
+			base: "a\nb\nc\n", ours: "a\nb\nC\n", theirs: "a\nb\nc\n",
+			want: "a\nb\nC\n",
+		},
+		{
+			name: "same change on both sides",
+			base: "a\nb\nc\n", ours: "a\nX\nc\n", theirs: "a\nX\nc\n",
+			want: "a\nX\nc\n",
+		},
+		{
+			name: "separate edits",
+			base: "a\nb\nc\nd\ne\n", ours: "a\nB\nc\nd\ne\n", theirs: "a\nb\nc\nD\ne\n",
+			want: "a\nB\nc\nD\ne\n",
+		},
+		{
+			name: "insertions at both ends",
+			base: "b\n", ours: "a\nb\n", theirs: "b\nc\n",
+			want: "a\nb\nc\n",
+		},
+		{
+			name: "deletion against an untouched line",
+			base: "a\nb\nc\n", ours: "a\nc\n", theirs: "a\nb\nc\nd\n",
+			want: "a\nc\nd\n",
+		},
+		{
+			// With no unchanged line between them the edits form one chunk
+			name: "adjacent edits conflict",
+			base: "a\nb\nc\nd\n", ours: "a\nB\nc\nd\n", theirs: "a\nb\nC\nd\n",
+			want:      "a\n<<<<<<< ours\nB\nc\n=======\nb\nC\n>>>>>>> theirs\nd\n",
+			conflicts: 1,
+		},
+		{
+			name: "overlapping edits conflict",
+			base: "a\nb\nc\n", ours: "a\nX\nc\n", theirs: "a\nY\nc\n",
+			want:      "a\n<<<<<<< ours\nX\n=======\nY\n>>>>>>> theirs\nc\n",
+			conflicts: 1,
+		},
+		{
+			name: "edit against deletion conflicts",
+			base: "a\nb\nc\n", ours: "a\nX\nc\n", theirs: "a\nc\n",
+			want:      "a\n<<<<<<< ours\nX\n=======\n>>>>>>> theirs\nc\n",
+			conflicts: 1,
+		},
+		{
+			name: "conflicts counted separately",
+			base: "a\nb\nc\nd\ne\n", ours: "X\nb\nc\nd\nX\n", theirs: "Y\nb\nc\nd\nY\n",
+			want:      "<<<<<<< ours\nX\n=======\nY\n>>>>>>> theirs\nb\nc\nd\n<<<<<<< ours\nX\n=======\nY\n>>>>>>> theirs\n",
+			conflicts: 2,
+		},
+		{
+			name: "markers end lines without a trailing newline",
+			base: "a\nb", ours: "a\nX", theirs: "a\nY",
+			want:      "a\n<<<<<<< ours\nX\n=======\nY\n>>>>>>> theirs\n",
+			conflicts: 1,
+		},
+		{
+			name: "separate words", byWord: true,
+			base: "the quick brown fox", ours: "the slow brown fox", theirs: "the quick brown cat",
+			want: "the slow brown cat",
+		},
+		{
+			name: "same word", byWord: true,
+			base: "<p>hello world</p>", ours: "<p>hello there</p>", theirs: "<p>hello friend</p>",
+			want:      "<p>hello <<<<<<< ours\nthere\n=======\nfriend\n>>>>>>> theirs\n</p>",
+			conflicts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			split := SplitLines
+			if tt.byWord {
+				split = SplitWords
+			}
+			got, conflicts := Merge3(split(tt.base), split(tt.ours), split(tt.theirs), "ours", "theirs")
+			if got != tt.want || conflicts != tt.conflicts {
+				t.Errorf("Merge3 = %q (%d conflicts), want %q (%d)", got, conflicts, tt.want, tt.conflicts)
+			}
+		})
+	}
+}
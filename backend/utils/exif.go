@@ -0,0 +1,198 @@
+package utils
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+)
+
+var (
+	errBadWebP = errors.New("malformed WebP file")
+	errBadGIF  = errors.New("malformed GIF file")
+)
+
+// JPEGOrientation reads the EXIF orientation (1-8) of a JPEG file, 1 when
+// it has none. Cameras store photos as shot and record the rotation here.
+func JPEGOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	for pos := 2; pos+4 <= len(data); {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		if marker == 0xDA || marker == 0xD9 { // Image data starts; no EXIF before it
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return 1
+		}
+		if marker == 0xE1 && length >= 8 && string(data[pos+4:pos+10]) == "Exif\x00\x00" {
+			return tiffOrientation(data[pos+10 : end])
+		}
+		pos = end
+	}
+	return 1
+}
+
+// tiffOrientation finds tag 0x0112 in the first IFD of an EXIF TIFF block
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[ifd : ifd+2]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 {
+			if o := int(order.Uint16(tiff[entry+8 : entry+10])); o >= 1 && o <= 8 {
+				return o
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// Orient turns an image the way its EXIF orientation says it should be shown
+func Orient(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orientation >= 5 { // Quarter turns swap width and height
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // Mirrored
+				dx, dy = w-1-x, y
+			case 3: // Upside down
+				dx, dy = w-1-x, h-1-y
+			case 4: // Upside down and mirrored
+				dx, dy = x, h-1-y
+			case 5: // Mirrored and turned left
+				dx, dy = y, x
+			case 6: // Turned left; rotate right to fix
+				dx, dy = h-1-y, x
+			case 7: // Mirrored and turned right
+				dx, dy = h-1-y, w-1-x
+			case 8: // Turned right; rotate left to fix
+				dx, dy = y, w-1-x
+			}
+			dst.Set(dx, dy, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+// StripWebPMetadata drops the EXIF and XMP chunks from a WebP file and
+// clears their flags, leaving the image data as it was
+func StripWebPMetadata(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, errBadWebP
+	}
+	out := append([]byte{}, data[:12]...)
+	for pos := 12; pos < len(data); {
+		if pos+8 > len(data) {
+			return nil, errBadWebP
+		}
+		id := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		end := pos + 8 + size + size%2 // Chunks are padded to an even length
+		if pos+8+size > len(data) {
+			return nil, errBadWebP
+		}
+		if end > len(data) {
+			end = len(data)
+		}
+		chunk := data[pos:end]
+		switch id {
+		case "EXIF", "XMP ":
+			// dropped
+		case "VP8X":
+			chunk = append([]byte{}, chunk...)
+			if len(chunk) > 8 {
+				chunk[8] &^= 0x08 | 0x04 // EXIF and XMP present flags
+			}
+			out = append(out, chunk...)
+		default:
+			out = append(out, chunk...)
+		}
+		pos = end
+	}
+	binary.LittleEndian.PutUint32(out[4:8], uint32(len(out)-8))
+	return out, nil
+}
+
+// GIFPixels adds up the pixels of every frame of a GIF file by walking its
+// blocks, without decompressing anything. Decoding allocates each frame in
+// full, so this is what a decode costs.
+func GIFPixels(data []byte) (int, error) {
+	if len(data) < 13 || (string(data[:6]) != "GIF87a" && string(data[:6]) != "GIF89a") {
+		return 0, errBadGIF
+	}
+	pos := 13 + colorTableSize(data[10])
+	total := 0
+	for pos < len(data) {
+		switch data[pos] {
+		case 0x21: // Extension: label, then data sub-blocks
+			pos += 2
+		case 0x2C: // Image descriptor
+			if pos+10 > len(data) {
+				return 0, errBadGIF
+			}
+			width := int(binary.LittleEndian.Uint16(data[pos+5 : pos+7]))
+			height := int(binary.LittleEndian.Uint16(data[pos+7 : pos+9]))
+			total += width * height
+			pos += 10 + colorTableSize(data[pos+9]) + 1 // Local colors, LZW code size
+		case 0x3B: // Trailer
+			return total, nil
+		default:
+			return 0, errBadGIF
+		}
+		// Skip the sub-blocks, each led by its length, up to an empty one
+		for {
+			if pos >= len(data) {
+				return 0, errBadGIF
+			}
+			n := int(data[pos])
+			pos += 1 + n
+			if n == 0 {
+				break
+			}
+		}
+	}
+	return 0, errBadGIF // No trailer
+}
+
+// colorTableSize is the length of the color table a GIF flags byte announces
+func colorTableSize(flags byte) int {
+	if flags&0x80 == 0 {
+		return 0
+	}
+	return 3 << ((flags & 0x07) + 1)
+}
@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	stddraw "image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+// ErrUnsupportedImage is returned for formats CleanImage can't handle
+var ErrUnsupportedImage = errors.New("unsupported image format")
+
+// CleanedImage is an upload decoded and written out again without metadata
+type CleanedImage struct {
+	Data   []byte
+	Image  image.Image // First frame for animations
+	Width  int
+	Height int
+}
+
+// CleanImage decodes an image of the given MIME type in full and encodes
+// it again, which drops EXIF (including GPS), comments and other metadata.
+// JPEGs are turned upright first, as their orientation lives in the EXIF.
+// There is no WebP encoder, so WebP files keep their image data and only
+// lose their metadata chunks.
+func CleanImage(data []byte, mimeType string) (*CleanedImage, error) {
+	var buf bytes.Buffer
+	switch mimeType {
+	case "image/jpeg":
+		img, err := jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		img = Orient(img, JPEGOrientation(data))
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90}); err != nil {
+			return nil, err
+		}
+		return cleaned(buf.Bytes(), img), nil
+	case "image/png":
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+		return cleaned(buf.Bytes(), img), nil
+	case "image/gif":
+		g, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if err := gif.EncodeAll(&buf, g); err != nil {
+			return nil, err
+		}
+		return &CleanedImage{Data: buf.Bytes(), Image: g.Image[0], Width: g.Config.Width, Height: g.Config.Height}, nil
+	case "image/webp":
+		img, err := webp.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		stripped, err := StripWebPMetadata(data)
+		if err != nil {
+			return nil, err
+		}
+		return cleaned(stripped, img), nil
+	}
+	return nil, ErrUnsupportedImage
+}
+
+func cleaned(data []byte, img image.Image) *CleanedImage {
+	return &CleanedImage{Data: data, Image: img, Width: img.Bounds().Dx(), Height: img.Bounds().Dy()}
+}
+
+// ImageSize reads an image's dimensions from its header without decoding
+// the pixels. Formats without a registered decoder return an error.
+func ImageSize(r io.Reader) (width, height int, err error) {
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return 0, 0, err
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+// ResizeWidth scales img to the given width, keeping its aspect ratio
+func ResizeWidth(img image.Image, width int) image.Image {
+	b := img.Bounds()
+	height := b.Dy() * width / b.Dx()
+	if height < 1 {
+		height = 1
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Over, nil)
+	return dst
+}
+
+// EncodeImage writes img as "jpeg", "gif" or "png". JPEG has no
+// transparency, so transparent areas come out white.
+func EncodeImage(w io.Writer, img image.Image, format string) error {
+	switch format {
+	case "jpeg":
+		flat := image.NewRGBA(img.Bounds())
+		stddraw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, stddraw.Src)
+		stddraw.Draw(flat, flat.Bounds(), img, img.Bounds().Min, stddraw.Over)
+		return jpeg.Encode(w, flat, &jpeg.Options{Quality: 85})
+	case "gif":
+		return gif.Encode(w, img, nil)
+	}
+	return png.Encode(w, img)
+}
@@ -10,19 +10,21 @@ import (
 )
 
 type Claims struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	IsAdmin  bool   `json:"is_admin"`
+	UserID   string   `json:"user_id"`
+	Username string   `json:"username"`
+	IsAdmin  bool     `json:"is_admin"`
+	Roles    []string `json:"roles"`
 	jwt.RegisteredClaims
 }
 
-func GenerateJWT(userID, username string, isAdmin bool) (string, error) {
+func GenerateJWT(userID, username string, isAdmin bool, roles []string) (string, error) {
 	cfg := config.GetConfig()
-	
+
 	claims := &Claims{
 		UserID:   userID,
 		Username: username,
 		IsAdmin:  isAdmin,
+		Roles:    roles,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -35,7 +37,7 @@ func GenerateJWT(userID, username string, isAdmin bool) (string, error) {
 
 func ValidateJWT(tokenString string) (*Claims, error) {
 	cfg := config.GetConfig()
-	
+
 	claims := &Claims{}
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
 		return []byte(cfg.JWTSecret), nil
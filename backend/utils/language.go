@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// languageCodes maps ISO 639-1 codes, as sent in Accept-Language, to the
+// language names stored in Blog.Language
+var languageCodes = map[string]string{
+	"en": "english",
+	"hi": "hindi",
+	"es": "spanish",
+	"fr": "french",
+	"de": "german",
+	"it": "italian",
+	"pt": "portuguese",
+	"nl": "dutch",
+	"ru": "russian",
+	"bn": "bengali",
+	"mr": "marathi",
+	"ta": "tamil",
+	"te": "telugu",
+	"gu": "gujarati",
+	"pa": "punjabi",
+	"ur": "urdu",
+}
+
+// PreferredLanguages parses an Accept-Language header into Blog.Language
+// names, most preferred first. Example: "hi-IN,hi;q=0.9,en;q=0.8" -> [hindi english]
+func PreferredLanguages(header string) []string {
+	type weighted struct {
+		name string
+		q    float64
+	}
+	var langs []weighted
+	seen := map[string]bool{}
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		base := strings.SplitN(tag, "-", 2)[0]
+		name, ok := languageCodes[base]
+		if !ok {
+			name = base // allow full names such as "hindi"
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		langs = append(langs, weighted{name: name, q: q})
+	}
+
+	sort.SliceStable(langs, func(i, j int) bool { return langs[i].q > langs[j].q })
+	names := make([]string, len(langs))
+	for i, l := range langs {
+		names[i] = l.name
+	}
+	return names
+}
@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	"kunals-blog-backend/config"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+// Posts come from trusted staff and get a broad allowlist. Comments are
+// public and anonymous, so they get a small one and links are nofollow.
+var (
+	policiesOnce  sync.Once
+	postPolicy    *bluemonday.Policy
+	commentPolicy *bluemonday.Policy
+)
+
+// linkTargets are the target values allowed on links. A named window could
+// be reached through window.opener just like a new tab.
+var linkTargets = regexp.MustCompile(`^_(blank|self)$`)
+
+func loadPolicies() {
+	cfg := config.GetConfig()
+
+	postPolicy = buildPolicy(cfg.SanitizePostTags, cfg.SanitizePostAttrs)
+	postPolicy.AllowRelativeURLs(true)
+	postPolicy.AllowDataURIImages()
+	if styles := splitList(cfg.SanitizePostStyles); len(styles) > 0 {
+		postPolicy.AllowStyles(styles...).Globally()
+	}
+
+	commentPolicy = buildPolicy(cfg.SanitizeCommentTags, cfg.SanitizeCommentAttrs)
+	commentPolicy.RequireNoFollowOnLinks(true)
+	commentPolicy.AddTargetBlankToFullyQualifiedLinks(true)
+}
+
+// buildPolicy turns the config allowlists into a policy. Links that open a
+// new tab always get rel="noopener", and external ones rel="noreferrer",
+// so the opened page can't take over this one (reverse tabnabbing).
+func buildPolicy(tags, attrs string) *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+	p.AllowStandardURLs()
+	p.RequireNoReferrerOnFullyQualifiedLinks(true)
+	p.AllowElements(splitList(tags)...)
+	for _, entry := range splitList(attrs) {
+		element, names, ok := strings.Cut(entry, ":")
+		if !ok {
+			continue
+		}
+		var plain []string
+		for _, name := range strings.Split(names, "|") {
+			if name == "target" {
+				allow(p.AllowAttrs(name).Matching(linkTargets), element)
+				continue
+			}
+			plain = append(plain, name)
+		}
+		if len(plain) > 0 {
+			allow(p.AllowAttrs(plain...), element)
+		}
+	}
+	return p
+}
+
+// attrRule is what bluemonday's AllowAttrs returns
+type attrRule interface {
+	Globally() *bluemonday.Policy
+	OnElements(elements ...string) *bluemonday.Policy
+}
+
+// allow applies an attribute rule to element, or to all of them for "*"
+func allow(attrs attrRule, element string) {
+	if element == "*" {
+		attrs.Globally()
+	} else {
+		attrs.OnElements(element)
+	}
+}
+
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// SanitizePost cleans HTML written by staff for a blog post
+func SanitizePost(html string) string {
+	policiesOnce.Do(loadPolicies)
+	return postPolicy.Sanitize(html)
+}
+
+// SanitizeComment cleans HTML submitted in a public comment
+func SanitizeComment(html string) string {
+	policiesOnce.Do(loadPolicies)
+	return commentPolicy.Sanitize(html)
+}
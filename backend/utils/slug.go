@@ -0,0 +1,66 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+const maxSlugRunes = 80
+
+// symbolWords spells out symbols that tell names apart, so "C++", "C#" and
+// "C" get different slugs. They count only straight after a letter, digit
+// or the same symbol, so "#1" is still just "1".
+var symbolWords = map[rune]string{'+': "plus", '#': "sharp"}
+
+// Slugify turns a title into a URL-friendly slug. Letters, digits and
+// combining marks from any script are kept so Hindi titles stay readable.
+// Example: "Hello, World!" -> "hello-world", "C++" -> "c-plus-plus".
+// Falls back to "post".
+func Slugify(s string) string {
+	return slugify(s, "post")
+}
+
+func slugify(s, fallback string) string {
+	var b strings.Builder
+	count := 0
+	pendingDash := false
+	var prev rune
+	spelled := false // prev was spelled out
+	for _, r := range strings.ToLower(s) {
+		if count >= maxSlugRunes {
+			break
+		}
+		if word, ok := symbolWords[r]; ok && (isSlugRune(prev) || (spelled && prev == r)) {
+			if b.Len() > 0 {
+				b.WriteRune('-')
+				count++
+			}
+			b.WriteString(word)
+			count += len(word)
+			pendingDash = true
+			prev, spelled = r, true
+			continue
+		}
+		prev, spelled = r, false
+		if isSlugRune(r) {
+			if pendingDash && b.Len() > 0 {
+				b.WriteRune('-')
+				count++
+			}
+			pendingDash = false
+			b.WriteRune(r)
+			count++
+			continue
+		}
+		pendingDash = true
+	}
+	slug := strings.Trim(b.String(), "-")
+	if slug == "" {
+		return fallback
+	}
+	return slug
+}
+
+func isSlugRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r)
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// stopWords are common English words that say nothing about a topic
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true, "you": true,
+	"all": true, "any": true, "can": true, "had": true, "her": true, "was": true, "one": true,
+	"our": true, "out": true, "has": true, "have": true, "this": true, "that": true, "with": true,
+	"from": true, "they": true, "will": true, "would": true, "there": true, "their": true,
+	"what": true, "about": true, "which": true, "when": true, "your": true, "into": true,
+	"than": true, "then": true, "them": true, "these": true, "some": true, "also": true,
+	"its": true, "his": true, "she": true, "how": true, "who": true, "been": true, "were": true,
+	"more": true, "just": true, "like": true, "only": true, "other": true, "such": true,
+	"very": true, "here": true, "where": true, "while": true, "should": true, "could": true,
+}
+
+// Terms splits text into lowercase words for similarity scoring, dropping
+// stop words and words shorter than three characters
+func Terms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	})
+	terms := words[:0]
+	for _, w := range words {
+		if len([]rune(w)) < 3 || stopWords[w] {
+			continue
+		}
+		terms = append(terms, w)
+	}
+	return terms
+}
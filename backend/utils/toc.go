@@ -0,0 +1,161 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// TOCEntry is one heading in a post's table of contents
+type TOCEntry struct {
+	ID       string     `json:"id"`
+	Text     string     `json:"text"`
+	Level    int        `json:"level"`
+	Children []TOCEntry `json:"children,omitempty"`
+}
+
+var headingLevels = map[string]int{"h1": 1, "h2": 2, "h3": 3, "h4": 4, "h5": 5, "h6": 6}
+
+type heading struct {
+	level int
+	id    string
+	text  string
+	hasID bool
+}
+
+// BuildTOC gives every heading in an HTML fragment an anchor ID derived
+// from its text (existing IDs are kept) and returns the rewritten HTML
+// together with the heading hierarchy.
+func BuildTOC(fragment string) (string, []TOCEntry) {
+	headings := scanHeadings(fragment)
+	if len(headings) == 0 {
+		return fragment, nil
+	}
+
+	used := map[string]bool{}
+	for _, h := range headings {
+		if h.hasID {
+			used[h.id] = true
+		}
+	}
+	for i := range headings {
+		if headings[i].hasID {
+			continue
+		}
+		base := slugify(headings[i].text, "section")
+		id := base
+		for n := 1; used[id]; n++ {
+			id = fmt.Sprintf("%s-%d", base, n)
+		}
+		used[id] = true
+		headings[i].id = id
+	}
+
+	return addHeadingIDs(fragment, headings), nestHeadings(headings)
+}
+
+// scanHeadings collects headings in document order
+func scanHeadings(fragment string) []heading {
+	var headings []heading
+	var current *heading
+	var text strings.Builder
+	z := html.NewTokenizer(strings.NewReader(fragment))
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			return headings
+		case html.StartTagToken:
+			tok := z.Token()
+			if level, ok := headingLevels[tok.Data]; ok && current == nil {
+				current = &heading{level: level}
+				for _, attr := range tok.Attr {
+					if attr.Key == "id" && attr.Val != "" {
+						current.id, current.hasID = attr.Val, true
+					}
+				}
+				text.Reset()
+			}
+		case html.TextToken:
+			if current != nil {
+				text.Write(z.Text())
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			if current != nil && headingLevels[string(name)] == current.level {
+				current.text = strings.Join(strings.Fields(text.String()), " ")
+				headings = append(headings, *current)
+				current = nil
+			}
+		}
+	}
+}
+
+// addHeadingIDs writes the fragment back, adding IDs to headings without one
+func addHeadingIDs(fragment string, headings []heading) string {
+	var b strings.Builder
+	next := 0
+	inHeading := false
+	z := html.NewTokenizer(strings.NewReader(fragment))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return b.String()
+		}
+		raw := string(z.Raw())
+		switch tt {
+		case html.StartTagToken:
+			tok := z.Token()
+			if _, ok := headingLevels[tok.Data]; ok && !inHeading && next < len(headings) {
+				inHeading = true
+				h := headings[next]
+				next++
+				if !h.hasID {
+					tok.Attr = append(tok.Attr, html.Attribute{Key: "id", Val: h.id})
+					raw = tok.String()
+				}
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			if _, ok := headingLevels[string(name)]; ok {
+				inHeading = false
+			}
+		}
+		b.WriteString(raw)
+	}
+}
+
+// nestHeadings turns the flat heading list into a tree; a heading belongs
+// under the nearest earlier heading with a smaller level
+func nestHeadings(headings []heading) []TOCEntry {
+	type node struct {
+		entry    TOCEntry
+		children []*node
+	}
+	root := &node{}
+	stack := []*node{root}
+	for _, h := range headings {
+		n := &node{entry: TOCEntry{ID: h.id, Text: h.text, Level: h.level}}
+		for len(stack) > 1 && stack[len(stack)-1].entry.Level >= h.level {
+			stack = stack[:len(stack)-1]
+		}
+		parent := stack[len(stack)-1]
+		parent.children = append(parent.children, n)
+		stack = append(stack, n)
+	}
+
+	var build func(nodes []*node) []TOCEntry
+	build = func(nodes []*node) []TOCEntry {
+		if len(nodes) == 0 {
+			return nil
+		}
+		entries := make([]TOCEntry, len(nodes))
+		for i, n := range nodes {
+			entries[i] = n.entry
+			entries[i].Children = build(n.children)
+		}
+		return entries
+	}
+	return build(root.children)
+}
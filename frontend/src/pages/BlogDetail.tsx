@@ -17,7 +17,7 @@ import { EyeIcon } from '@heroicons/react/24/outline';
 import { publicBlogAPI } from '../utils/api';
 import { useAuth } from '../contexts/AuthContext';
 import type { Blog, Comment, CreateCommentRequest } from '../types';
-import { formatDate, timeAgo, stripHtml } from '../utils/helpers';
+import { formatDate, timeAgo, stripHtml, extractImageUrls } from '../utils/helpers';
 import { Loading } from '../components/ui/Loading';
 import Button from '../components/ui/Button';
 import { Card } from '../components/ui/Card';
@@ -201,7 +201,7 @@ const BlogDetail: React.FC = () => {
           </h1>
 
           {/* Blog Image */}
-          {blog.images && (
+          {extractImageUrls(blog.images).length > 0 && (
             <motion.div
               initial={{ opacity: 0, scale: 0.95 }}
               animate={{ opacity: 1, scale: 1 }}
@@ -209,8 +209,8 @@ const BlogDetail: React.FC = () => {
               className="mb-8 rounded-2xl overflow-hidden shadow-2xl"
             >
               <img
-                src={`${(import.meta as any).env?.VITE_ASSET_BASE || import.meta.env.VITE_ASSET_BASE || 'http://localhost:8080'}${blog.images.split(',')[0]}`}
-                alt={blog.title}
+                src={`${(import.meta as any).env?.VITE_ASSET_BASE || import.meta.env.VITE_ASSET_BASE || 'http://localhost:8080'}${extractImageUrls(blog.images)[0]}`}
+                alt={blog.images?.[0]?.media?.alt_text || blog.title}
                 className="w-full h-64 sm:h-96 object-cover"
               />
             </motion.div>
@@ -226,7 +226,7 @@ const BlogDetail: React.FC = () => {
           <Card className="p-8 mb-8">
             <div 
               className="prose prose-lg max-w-none prose-headings:text-gray-900 prose-p:text-gray-700 prose-strong:text-gray-900 prose-a:text-blue-600 prose-a:no-underline hover:prose-a:underline whitespace-pre-wrap break-words tab-size-[4] [&_p]:whitespace-pre-wrap [&_li]:whitespace-pre-wrap [&_p:empty]:h-4"
-              dangerouslySetInnerHTML={{ __html: blog.content }}
+              dangerouslySetInnerHTML={{ __html: blog.content_html || blog.content }}
             />
           </Card>
         </motion.div>
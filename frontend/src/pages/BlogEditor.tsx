@@ -2,7 +2,7 @@ import React, { useState, useEffect } from 'react';
 import { useParams, useNavigate } from 'react-router-dom';
 import { Save, Eye, Calendar, Globe, ArrowLeft } from 'lucide-react';
 import RichTextEditor from '../components/RichTextEditor';
-import { adminBlogAPI, publicBlogAPI } from '../utils/api';
+import { adminBlogAPI } from '../utils/api';
 import type { Blog, CreateBlogRequest, UpdateBlogRequest, BlogVersion } from '../types';
 import { formatDateTime } from '../utils/helpers';
 import toast from 'react-hot-toast';
@@ -24,6 +24,7 @@ const BlogEditor: React.FC = () => {
   const [versionsPage, setVersionsPage] = useState(1);
   const versionsPerPage = 5;
   const [activeVersionPreview, setActiveVersionPreview] = useState<BlogVersion | null>(null);
+  const [savedDraft, setSavedDraft] = useState<BlogVersion | null>(null);
 
   // Load existing blog if editing
   useEffect(() => {
@@ -32,14 +33,31 @@ const BlogEditor: React.FC = () => {
     }
   }, [isEditing, id]);
 
+  // Autosave edits to the working draft a few seconds after typing stops
+  useEffect(() => {
+    if (!isEditing || !id || !blog) return;
+    if (title === blog.title && content === blog.content && language === blog.language) return;
+    const timer = setTimeout(() => {
+      adminBlogAPI.autosave(id, { title: title.trim(), content, language }).catch(() => {});
+    }, 5000);
+    return () => clearTimeout(timer);
+  }, [isEditing, id, blog, title, content, language]);
+
   const loadBlog = async (blogId: string) => {
     try {
       setLoading(true);
-      const response = await publicBlogAPI.getBlog(blogId);
+      const [response, versionsResponse, draft] = await Promise.all([
+        adminBlogAPI.getBlog(blogId),
+        adminBlogAPI.getVersions(blogId),
+        adminBlogAPI.getAutosave(blogId).catch(() => null),
+      ]);
       const blogData = response.blog;
-      const vers = (response as any).versions || [];
+      const vers = versionsResponse.versions || [];
       
       setBlog(blogData);
+      // Offer back a working draft that was never saved as a version
+      const unsaved = draft && (draft.title !== blogData.title || draft.content !== blogData.content || draft.language !== blogData.language);
+      setSavedDraft(unsaved ? draft : null);
       setTitle(blogData.title);
       setContent(blogData.content);
       setLanguage(blogData.language as 'english' | 'devanagari');
@@ -77,11 +95,13 @@ const BlogEditor: React.FC = () => {
           content: content.trim(),
           language,
           custom_date: customDate || undefined,
+          base_revision: blog?.revision,
         };
         if (publish) {
           await adminBlogAPI.updateBlog(id, updateData);
           await adminBlogAPI.publishBlog(id);
           toast.success('Published latest draft');
+          await loadBlog(id);
         } else {
           await adminBlogAPI.updateBlog(id, updateData);
           // flip button label to Publish in UI by marking blog unpublished locally
@@ -113,6 +133,7 @@ const BlogEditor: React.FC = () => {
         navigate('/admin');
       }
     } catch (error: any) {
+      if (handleConflict(error)) return;
       const errorMessage = error.response?.data?.error || 'Failed to save blog';
       toast.error(errorMessage);
     } finally {
@@ -120,6 +141,35 @@ const BlogEditor: React.FC = () => {
     }
   };
 
+  // Someone else saved the post since we loaded it: keep the editor text so
+  // nothing is lost, but pick up the new revision and tell the user
+  const handleConflict = (error: any): boolean => {
+    if (error.response?.status !== 409 || !error.response?.data?.blog) return false;
+    const current: Blog = error.response.data.blog;
+    setBlog(current);
+    toast.error('This post was changed elsewhere. Review the latest version before saving again.');
+    return true;
+  };
+
+  const restoreDraft = () => {
+    if (!savedDraft) return;
+    setTitle(savedDraft.title);
+    setContent(savedDraft.content);
+    setLanguage(savedDraft.language as 'english' | 'devanagari');
+    setSavedDraft(null);
+    toast.success('Draft restored');
+  };
+
+  const discardDraft = async () => {
+    if (!id) return;
+    try {
+      await adminBlogAPI.discardAutosave(id);
+    } catch {
+      // Already gone
+    }
+    setSavedDraft(null);
+  };
+
   const handlePublishToggle = async () => {
     if (!isEditing || !id || !blog) return;
 
@@ -132,7 +182,11 @@ const BlogEditor: React.FC = () => {
       } else {
         // If previewing a version, apply that version first
         if (activeVersionPreview) {
-          await adminBlogAPI.applyVersion(id, activeVersionPreview.id);
+          if (activeVersionPreview.status === 'pending') {
+            await adminBlogAPI.applyVersion(id, activeVersionPreview.id, blog.revision);
+          } else {
+            await adminBlogAPI.restoreVersion(id, activeVersionPreview.id, blog.revision);
+          }
         } else {
           // Otherwise create a draft version from current editor state and apply it
           const updateData: UpdateBlogRequest = {
@@ -140,11 +194,13 @@ const BlogEditor: React.FC = () => {
             content: content.trim(),
             language,
             custom_date: customDate || undefined,
+            base_revision: blog.revision,
           };
           const resp: any = await adminBlogAPI.updateBlog(id, updateData);
           const versionId = resp?.version?.id;
           if (versionId) {
-            await adminBlogAPI.applyVersion(id, versionId);
+            // metadata changes in the update may have bumped the revision
+            await adminBlogAPI.applyVersion(id, versionId, resp.blog?.revision ?? blog.revision);
           }
         }
         await adminBlogAPI.publishBlog(id);
@@ -157,6 +213,7 @@ const BlogEditor: React.FC = () => {
       // Reload blog data
       await loadBlog(id);
     } catch (error: any) {
+      if (handleConflict(error)) return;
       const errorMessage = error.response?.data?.error || 'Failed to update blog status';
       toast.error(errorMessage);
     } finally {
@@ -193,6 +250,27 @@ const BlogEditor: React.FC = () => {
           </div>
         </div>
       )}
+      {savedDraft && (
+        <div className="mb-6 flex items-center justify-between bg-yellow-50 border border-yellow-200 rounded-lg p-3 text-sm">
+          <span className="text-yellow-800">
+            You have unsaved changes from {formatDateTime(savedDraft.updated_at)}.
+          </span>
+          <div className="flex items-center space-x-2">
+            <button
+              onClick={restoreDraft}
+              className="px-3 py-1 rounded-md bg-yellow-600 text-white hover:bg-yellow-700 transition-colors"
+            >
+              Restore
+            </button>
+            <button
+              onClick={discardDraft}
+              className="px-3 py-1 rounded-md border border-yellow-300 text-yellow-800 hover:bg-yellow-100 transition-colors"
+            >
+              Discard
+            </button>
+          </div>
+        </div>
+      )}
       {/* Header */}
       <div className="mb-8">
         <div className="flex items-center justify-between">
@@ -5,7 +5,7 @@ import { CalendarIcon, HeartIcon, ChatBubbleLeftIcon, GlobeAltIcon, EyeIcon, Che
 import { useInView } from 'react-intersection-observer';
 import type { Blog } from '../types';
 import { publicBlogAPI } from '../utils/api';
-import { formatDate, timeAgo, stripHtml } from '../utils/helpers';
+import { formatDate, timeAgo, stripHtml, extractImageUrls, assetSrcSet } from '../utils/helpers';
 import { Card } from '../components/ui/Card';
 import { Badge } from '../components/ui/Badge';
 import Button from '../components/ui/Button';
@@ -125,11 +125,13 @@ const Home: React.FC = () => {
                   <Link to={`/blog/${blog.id}`} className="block h-full group focus:outline-none">
                     <Card hover className="h-full overflow-hidden">
                       {/* Blog Image (if available) */}
-                      {blog.images && (
+                      {extractImageUrls(blog.images).length > 0 && (
                         <div className="aspect-video bg-gradient-to-br from-gray-100 to-gray-200 overflow-hidden">
                           <img
-                            src={`${ASSET_BASE}${blog.images.split(',')[0]}`}
-                            alt={blog.title}
+                            src={`${ASSET_BASE}${extractImageUrls(blog.images)[0]}`}
+                            srcSet={assetSrcSet(blog.images?.[0]?.media?.srcset, ASSET_BASE)}
+                            sizes="(min-width: 1024px) 33vw, (min-width: 640px) 50vw, 100vw"
+                            alt={blog.images?.[0]?.media?.alt_text || blog.title}
                             className="w-full h-full object-cover group-hover:scale-110 transition-transform duration-500"
                           />
                         </div>
@@ -158,7 +160,7 @@ const Home: React.FC = () => {
                         <div
                           className="text-gray-600 mb-6 leading-relaxed not-prose whitespace-pre-wrap break-words tab-size-[4] [&_p]:whitespace-pre-wrap [&_li]:whitespace-pre-wrap [&_p:empty]:h-4 overflow-hidden"
                           style={{ maxHeight: '8rem' }}
-                          dangerouslySetInnerHTML={{ __html: blog.content }}
+                          dangerouslySetInnerHTML={{ __html: blog.content_html || blog.content }}
                         />
 
                         {/* Meta Info */}
@@ -10,20 +10,63 @@ export interface Blog {
   id: string;
   title: string;
   content: string;
+  content_format?: 'html' | 'markdown';
+  content_html?: string;
   preview: string;
   language: string;
-  images: string;
+  images?: BlogImage[];
   is_published: boolean;
   published_at?: string;
   custom_date?: string;
   likes_count: number;
   comments_count: number;
   views_count: number;
+  is_pinned?: boolean;
+  pin_order?: number;
+  pinned_until?: string;
+  is_featured?: boolean;
+  feature_order?: number;
+  featured_until?: string;
+  revision: number;
   created_at: string;
   updated_at: string;
   comments?: Comment[];
   likes?: Like[];
-  versions?: BlogVersion[];
+}
+
+export interface Media {
+  id: string;
+  filename: string;
+  original_name: string;
+  size: number;
+  mime_type: string;
+  width: number;
+  height: number;
+  alt_text: string;
+  caption: string;
+  uploader_id?: string;
+  variants?: ImageVariant[];
+  url: string;
+  srcset: string;
+  sha256: string;
+  ref_count: number;
+  created_at: string;
+  updated_at: string;
+  deleted_at?: string | null;
+}
+
+export interface ImageVariant {
+  name: 'thumb' | 'medium' | 'large';
+  width: number;
+  height: number;
+  filename: string;
+  url: string;
+}
+
+export interface BlogImage {
+  media_id: string;
+  position: number;
+  media?: Media;
 }
 
 export interface Comment {
@@ -49,9 +92,13 @@ export interface BlogVersion {
   title: string;
   content: string;
   language: string;
-  images: string;
+  media_ids?: string[];
+  status: 'pending' | 'applied' | 'rejected' | 'history' | 'merged' | 'autosave';
+  author_id?: string;
+  message?: string;
   is_pending: boolean;
   created_at: string;
+  updated_at: string;
 }
 
 export interface LoginRequest {
@@ -74,7 +121,7 @@ export interface CreateBlogRequest {
   title: string;
   content: string;
   language?: string;
-  images?: string[];
+  media_ids?: string[];
   custom_date?: string;
 }
 
@@ -82,8 +129,9 @@ export interface UpdateBlogRequest {
   title?: string;
   content?: string;
   language?: string;
-  images?: string[];
+  media_ids?: string[];
   custom_date?: string;
+  base_revision?: number;
 }
 
 export interface CreateCommentRequest {
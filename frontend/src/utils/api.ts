@@ -3,10 +3,13 @@ import type {
   LoginRequest,
   LoginResponse,
   Blog,
+  BlogVersion,
   CreateBlogRequest,
   UpdateBlogRequest,
   Comment,
   CreateCommentRequest,
+  Media,
+  ImageVariant,
   PaginationParams,
   SignupRequest,
 } from '../types';
@@ -57,11 +60,17 @@ export const publicBlogAPI = {
     if (params.published_only !== undefined) queryParams.append('published_only', params.published_only.toString());
     if (params.language) queryParams.append('language', params.language);
     if ((params as any).sort_by) queryParams.append('sort_by', (params as any).sort_by as string);
+    if ((params as any).pinned_first) queryParams.append('pinned_first', 'true');
 
     const response = await api.get(`/public/blogs?${queryParams.toString()}`);
     return response.data;
   },
 
+  getFeaturedBlogs: async (limit?: number): Promise<{ blogs: Blog[] }> => {
+    const response = await api.get('/public/blogs/featured', { params: limit ? { limit } : undefined });
+    return response.data;
+  },
+
   getBlog: async (id: string): Promise<{ blog: Blog }> => {
     const response = await api.get(`/public/blogs/${id}`);
     return response.data;
@@ -119,6 +128,12 @@ export const adminBlogAPI = {
     return response.data as { items: { id: string; created_at: string; display: string; user_id?: string; ip_address?: string }[]; pagination: any };
   },
 
+  // Any blog, drafts and scheduled posts included
+  getBlog: async (id: string): Promise<{ blog: Blog }> => {
+    const response = await api.get(`/admin/blogs/${id}`);
+    return response.data;
+  },
+
   createBlog: async (blog: CreateBlogRequest) => {
     const response = await api.post('/admin/blogs', blog);
     return response.data;
@@ -129,8 +144,40 @@ export const adminBlogAPI = {
     return response.data;
   },
 
-  applyVersion: async (id: string, versionId: string) => {
-    const response = await api.post(`/admin/blogs/${id}/versions/${versionId}/apply`);
+  getVersions: async (id: string, page = 1, limit = 100): Promise<{ versions: BlogVersion[]; pagination: any }> => {
+    const qs = new URLSearchParams({ page: String(page), limit: String(limit) });
+    const response = await api.get(`/admin/blogs/${id}/versions?${qs.toString()}`);
+    return response.data;
+  },
+
+  autosave: async (id: string, draft: { title: string; content: string; language: string }) => {
+    const response = await api.put(`/admin/blogs/${id}/autosave`, draft);
+    return response.data;
+  },
+
+  // The current editor's working draft, or null when there is none
+  getAutosave: async (id: string): Promise<BlogVersion | null> => {
+    try {
+      const response = await api.get(`/admin/blogs/${id}/autosave`);
+      return response.data.version as BlogVersion;
+    } catch (error: any) {
+      if (error.response?.status === 404) return null;
+      throw error;
+    }
+  },
+
+  discardAutosave: async (id: string) => {
+    const response = await api.delete(`/admin/blogs/${id}/autosave`);
+    return response.data;
+  },
+
+  applyVersion: async (id: string, versionId: string, baseRevision: number) => {
+    const response = await api.post(`/admin/blogs/${id}/versions/${versionId}/apply`, { base_revision: baseRevision });
+    return response.data;
+  },
+
+  restoreVersion: async (id: string, versionId: string, baseRevision: number) => {
+    const response = await api.post(`/admin/blogs/${id}/versions/${versionId}/restore`, { base_revision: baseRevision });
     return response.data;
   },
 
@@ -149,7 +196,7 @@ export const adminBlogAPI = {
     return response.data;
   },
 
-  uploadImage: async (file: File): Promise<{ url: string; filename: string }> => {
+  uploadImage: async (file: File): Promise<{ url: string; filename: string; srcset: string; variants: ImageVariant[]; media: Media; duplicate: boolean }> => {
     const formData = new FormData();
     formData.append('image', file);
     
@@ -162,4 +209,22 @@ export const adminBlogAPI = {
   },
 };
 
+// Admin Media Library API
+export const adminMediaAPI = {
+  getMedia: async (params: { page?: number; limit?: number; q?: string; type?: string; unused?: boolean } = {}) => {
+    const response = await api.get('/admin/media', { params });
+    return response.data;
+  },
+
+  updateMedia: async (id: string, data: { alt_text?: string; caption?: string }): Promise<{ media: Media }> => {
+    const response = await api.put(`/admin/media/${id}`, data);
+    return response.data;
+  },
+
+  deleteMedia: async (id: string) => {
+    const response = await api.delete(`/admin/media/${id}`);
+    return response.data;
+  },
+};
+
 export default api;
@@ -1,3 +1,5 @@
+import type { BlogImage } from '../types';
+
 export const cn = (...classes: (string | boolean | undefined)[]): string => {
   return classes.filter(Boolean).join(' ');
 };
@@ -55,9 +57,15 @@ export function stripHtml(input: string | undefined | null): string {
   return input.replace(/<[^>]*>/g, '').replace(/\s+/g, ' ').trim();
 }
 
-export function extractImageUrls(imagesString: string): string[] {
-  if (!imagesString) return [];
-  return imagesString.split(',').filter(url => url.trim() !== '');
+export function extractImageUrls(images: BlogImage[] | undefined): string[] {
+  if (!images) return [];
+  return images.filter(img => img.media).map(img => img.media!.url);
+}
+
+// Prefixes every URL in a srcset with the asset host
+export function assetSrcSet(srcset: string | undefined, base: string): string | undefined {
+  if (!srcset) return undefined;
+  return srcset.split(', ').map(entry => `${base}${entry}`).join(', ');
 }
 
 export function validateEmail(email: string): boolean {